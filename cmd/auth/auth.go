@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var AuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Login to tKeel and manage the cached token.",
+	Example: `
+tkeel auth login
+tkeel auth token
+tkeel auth logout
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	AuthCmd.Flags().BoolP("help", "h", false, "Print this help message")
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	password      string
+	passwordStdin bool
+)
+
+var AuthLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Login and cache the token used by the other commands.",
+	Example: `
+# Login, the password will be prompted for
+tkeel auth login
+
+# Login with the password read from stdin
+echo "<password>" | tkeel auth login --password-stdin
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if passwordStdin && password != "" {
+			print.FailureStatusEvent(os.Stdout, "--password and --password-stdin are mutually exclusive")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if passwordStdin {
+			b, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			password = strings.TrimRight(string(b), "\r\n")
+			if password == "" {
+				print.FailureStatusEvent(os.Stdout, "no password read from stdin")
+				os.Exit(1)
+			}
+		}
+		if password == "" {
+			err := survey.AskOne(&survey.Password{Message: "Please enter your password: "}, &password, survey.WithValidator(survey.Required))
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "failed to read password from stdin")
+				os.Exit(1)
+			}
+		}
+
+		if err := kubernetes.Login(password); err != nil {
+			print.FailureStatusEvent(os.Stdout, "Login Failed: %s", err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		print.SuccessStatusEvent(os.Stdout, "Login succeeded, the token is cached for the other commands")
+	},
+}
+
+func init() {
+	AuthLoginCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	AuthLoginCmd.Flags().StringVarP(&password, "password", "p", "", "The admin password")
+	AuthLoginCmd.Flags().BoolVarP(&passwordStdin, "password-stdin", "", false, "Read the password from stdin")
+	AuthCmd.AddCommand(AuthLoginCmd)
+}
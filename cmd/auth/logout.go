@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var AuthLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Clear the cached token.",
+	Example: `
+# Logout
+tkeel auth logout
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := kubernetes.Logout(); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Logged out")
+	},
+}
+
+func init() {
+	AuthLogoutCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	AuthCmd.AddCommand(AuthLogoutCmd)
+}
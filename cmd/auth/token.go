@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var AuthTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print the cached token.",
+	Example: `
+# Call a tKeel API with the cached token
+curl -H "Authorization: Bearer $(tkeel auth token)" <url>
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := kubernetes.CurrentToken()
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		fmt.Println(token)
+	},
+}
+
+func init() {
+	AuthTokenCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	AuthCmd.AddCommand(AuthTokenCmd)
+}
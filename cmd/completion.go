@@ -60,6 +60,12 @@ var completionExample = `
 	# Set the tkeel completion code for zsh[1] to autoload on startup
 	tkeel completion zsh > "${fpath[1]}/_tkeel"
 
+	# Installing fish completion
+	## Load the tkeel completion code for fish into the current shell
+	tkeel completion fish | source
+	## Write fish completion code to the completion directory to load it on startup
+	tkeel completion fish > ~/.config/fish/completions/tkeel.fish
+
 	# Installing powershell completion on Windows
 	## Create $PROFILE if it not exists
 	if (!(Test-Path -Path $PROFILE )){ New-Item -Type File -Path $PROFILE -Force }
@@ -80,6 +86,7 @@ func newCompletionCmd() *cobra.Command {
 	cmd.AddCommand(
 		newCompletionBashCmd(),
 		newCompletionZshCmd(),
+		newCompletionFishCmd(),
 		newCompletionPowerShellCmd(),
 	)
 
@@ -115,6 +122,19 @@ func newCompletionZshCmd() *cobra.Command {
 	return cmd
 }
 
+func newCompletionFishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fish",
+		Short: "Generates fish completion scripts",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = RootCmd.GenFishCompletion(os.Stdout, true)
+		},
+	}
+	cmd.Flags().BoolP("help", "h", false, "Print this help message")
+
+	return cmd
+}
+
 func newCompletionPowerShellCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "powershell",
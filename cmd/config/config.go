@@ -0,0 +1,44 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/config"
+)
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show default install config, manage the config file holding flag defaults and profiles.",
+	Example: `
+# Show default install config
+tkeel config
+
+# Save default install config to file
+tkeel config > config.yaml
+
+# Manage the flag defaults of ~/.tkeel/config.yaml
+tkeel config view
+tkeel config set <key> <value>
+tkeel config unset <key>
+tkeel config current-profile
+tkeel config use-profile <profile>
+`,
+	// the config commands must work even when the config file does not
+	// resolve, e.g. to switch away from a profile that was removed.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run: func(cmd *cobra.Command, args []string) {
+		printInstallConfig()
+	},
+}
+
+// configPath returns the config file given with --config or the default one.
+func configPath(cmd *cobra.Command) (string, error) {
+	file, _ := cmd.Flags().GetString("config")
+	return config.Path(file)
+}
+
+func init() {
+	ConfigCmd.Flags().StringVarP(&tenantHost, "tenant-host", "", "tkeel.io", "The host domain of tenant platform")
+	ConfigCmd.Flags().StringVarP(&adminHost, "admin-host", "", "admin.tkeel.io", "The host domain of admin platform")
+	ConfigCmd.Flags().StringVarP(&port, "port", "", "30080", "The port of ingress")
+	ConfigCmd.Flags().BoolP("help", "h", false, "Print this help message")
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/config"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var ConfigCurrentProfileCmd = &cobra.Command{
+	Use:   "current-profile",
+	Short: "Print the profile in use.",
+	Long: `Print the profile in use: the one given with --profile, then $KEEL_PROFILE,
+then the current profile of the config file.`,
+	Example: `
+tkeel config current-profile
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("profile")
+		if name == "" {
+			name = os.Getenv("KEEL_PROFILE")
+		}
+		if name == "" {
+			path, err := configPath(cmd)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			c, err := config.LoadOrEmpty(path)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			name = c.CurrentProfile
+		}
+		if name == "" {
+			print.WarningStatusEvent(os.Stdout, "No profile is in use, set one with tkeel config use-profile <profile>")
+			os.Exit(1)
+		}
+		fmt.Println(name)
+	},
+}
+
+func init() {
+	ConfigCurrentProfileCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigCmd.AddCommand(ConfigCurrentProfileCmd)
+}
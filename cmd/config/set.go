@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/config"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var ConfigSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a default in the config file.",
+	Long: `Set a default in the config file. The key is one of tenant, namespace,
+kubeconfig, context or output. With --profile the default is set in that
+profile, which is created if needed, otherwise at the top level of the file.`,
+	Example: `
+# Use the acme tenant by default
+tkeel config set tenant acme
+
+# Set the namespace of the staging profile
+tkeel config set namespace keel-system --profile staging
+`,
+	ValidArgsFunction: completeKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the key and the value")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel config set <key> <value>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if args[1] == "" {
+			print.WarningStatusEvent(os.Stdout, "Please specify a value, or remove the key with tkeel config unset %s", args[0])
+			os.Exit(kubernetes.ExitUsage)
+		}
+		name, _ := cmd.Flags().GetString("profile")
+		updateDefaults(cmd, name, true, func(d *config.Defaults) error {
+			return d.Set(args[0], args[1])
+		})
+		if name != "" {
+			print.SuccessStatusEvent(os.Stdout, "Set %s to %s in profile %s", args[0], args[1], name)
+			return
+		}
+		print.SuccessStatusEvent(os.Stdout, "Set %s to %s", args[0], args[1])
+	},
+}
+
+// updateDefaults applies update to the defaults of the named profile, or the
+// top level ones, and saves the config file. create adds a missing profile.
+func updateDefaults(cmd *cobra.Command, name string, create bool, update func(d *config.Defaults) error) {
+	path, err := configPath(cmd)
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+	c, err := config.LoadOrEmpty(path)
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+	d, err := c.Profile(name)
+	if err != nil && !create {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(kubernetes.ExitNotFound)
+	}
+	if err != nil {
+		d = &config.Defaults{}
+	}
+	if err = update(d); err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(kubernetes.ExitUsage)
+	}
+	c.SetProfile(name, *d)
+	if err = config.Save(path, c); err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+}
+
+func completeKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.Keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	ConfigSetCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigCmd.AddCommand(ConfigSetCmd)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/config"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var ConfigUnsetCmd = &cobra.Command{
+	Use:   "unset",
+	Short: "Remove a default from the config file.",
+	Example: `
+# Stop defaulting to a tenant
+tkeel config unset tenant
+
+# Remove the namespace of the staging profile
+tkeel config unset namespace --profile staging
+`,
+	ValidArgsFunction: completeKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the key")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel config unset <key>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		name, _ := cmd.Flags().GetString("profile")
+		updateDefaults(cmd, name, false, func(d *config.Defaults) error {
+			return d.Set(args[0], "")
+		})
+		print.SuccessStatusEvent(os.Stdout, "Unset %s", args[0])
+	},
+}
+
+func init() {
+	ConfigUnsetCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigCmd.AddCommand(ConfigUnsetCmd)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/config"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var ConfigUseProfileCmd = &cobra.Command{
+	Use:   "use-profile",
+	Short: "Set the current profile of the config file.",
+	Long: `Set the current profile of the config file. A profile bundles the defaults
+of one environment in the config file, ~/.tkeel/config.yaml:
+
+  current-profile: staging
+  profiles:
+    staging:
+      kubeconfig: ~/.kube/staging
+      context: staging
+      namespace: keel-system
+      tenant: acme`,
+	Example: `
+# Take the kubeconfig, context, namespace and tenant defaults from the staging profile
+tkeel config use-profile staging
+
+# Use the prod profile for a single command only
+tkeel plugin list --profile prod
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the profile")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel config use-profile <profile>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		path, err := configPath(cmd)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		c, err := config.LoadOrEmpty(path)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		if err = c.UseProfile(args[0]); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitNotFound)
+		}
+		if err = config.Save(path, c); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Switched to profile %s", args[0])
+	},
+}
+
+func init() {
+	ConfigUseProfileCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigCmd.AddCommand(ConfigUseProfileCmd)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/config"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/output"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+const redacted = "REDACTED"
+
+// resolvedConfig is the config in effect as printed by config view.
+type resolvedConfig struct {
+	Path    string `json:"path"`
+	Profile string `json:"profile,omitempty"`
+	config.Defaults
+	// Token tells whether a token is cached, never the token itself.
+	Token string `json:"token,omitempty"`
+}
+
+var ConfigViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the config in effect.",
+	Long: `Print the config in effect: the top level defaults of the config file with
+the ones of the profile in use applied over them. The cached token is redacted,
+print it with tkeel auth token.`,
+	Example: `
+tkeel config view
+tkeel config view --profile prod -o json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := output.ParseFormat(viewFormat)
+		if err != nil || format == output.TABLE {
+			print.FailureStatusEvent(os.Stdout, "Invalid output format %s, valid values are: json, yaml", viewFormat)
+			os.Exit(kubernetes.ExitUsage)
+		}
+		path, err := configPath(cmd)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		c, err := config.LoadOrEmpty(path)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		name, _ := cmd.Flags().GetString("profile")
+		if name == "" {
+			name = os.Getenv("KEEL_PROFILE")
+		}
+		d, err := c.Resolve(name)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitNotFound)
+		}
+		if name == "" {
+			name = c.CurrentProfile
+		}
+
+		view := resolvedConfig{Path: path, Profile: name, Defaults: *d}
+		switch _, err = kubernetes.CurrentToken(); {
+		case err == nil:
+			view.Token = redacted
+		case errors.Is(err, kubernetes.ErrTokenExpired):
+			view.Token = redacted + " (expired)"
+		}
+		if err = format.Write(os.Stdout, view); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var viewFormat string
+
+func init() {
+	ConfigViewCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	ConfigViewCmd.Flags().StringVarP(&viewFormat, "output", "o", "yaml", "The output format. Valid values are: json or yaml (default)")
+	ConfigCmd.AddCommand(ConfigViewCmd)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tkeel-io/cli/pkg/config"
+)
+
+// applyConfig fills the flags of cmd that were not given on the command line
+// from the KEEL_<FLAG> environment variables, then from the active profile
+// and the top level defaults of the config file.
+func applyConfig(cmd *cobra.Command) error {
+	viper.SetEnvPrefix("keel")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+
+	path, err := config.Path(defaultsFile)
+	if err != nil {
+		return err
+	}
+	c, err := config.Load(path)
+	switch {
+	case err == nil:
+	case defaultsFile == "" && errors.Is(err, os.ErrNotExist):
+		// the default config file is optional.
+		c = &config.Config{}
+	default:
+		return err
+	}
+	defaults, err := c.Resolve(activeProfile())
+	if err != nil {
+		return err
+	}
+	for _, key := range config.Keys {
+		if v := defaults.Get(key); v != "" {
+			viper.SetDefault(key, v)
+		}
+	}
+
+	for _, key := range config.Keys {
+		flag := cmd.Flags().Lookup(key)
+		if flag == nil || flag.Changed || !viper.IsSet(key) {
+			continue
+		}
+		// an --output-template given on the command line wins over a default format.
+		if key == config.KeyOutput && cmd.Flags().Changed("output-template") {
+			continue
+		}
+		// $KUBECONFIG is an environment variable too, it wins over the config file.
+		if key == config.KeyKubeconfig && os.Getenv("KEEL_KUBECONFIG") == "" && os.Getenv("KUBECONFIG") != "" {
+			continue
+		}
+		if err = config.SetFlag(cmd, key, viper.GetString(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activeProfile returns the profile chosen with --profile or $KEEL_PROFILE,
+// empty to use the current profile of the config file.
+func activeProfile() string {
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv("KEEL_PROFILE")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	healthOutput      string
+	healthComponents  []string
+	healthTimeout     time.Duration
+	healthConcurrency int
+)
+
+var HealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check the health of the tKeel control plane.",
+	Long: `Check the health of the components of the tKeel control plane: keel, rudder
+and core. A component is healthy when one of its pods is running and the
+healthz endpoint of its dapr sidecar answers through a port-forward within
+--timeout. The components are checked concurrently, and the command exits
+with 1 only when any of them is unhealthy, so that it can gate deployments.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Check the control plane is up before running other commands
+tkeel health
+
+# Check the control plane as JSON, for monitoring
+tkeel health -o json
+
+# Check only keel and core, giving each 3 seconds to answer
+tkeel health --component keel --component core --timeout 3s
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := fmtutil.CheckFormat(healthOutput); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+		list, err := kubernetes.ControlPlaneHealth(healthComponents, healthConcurrency, healthTimeout)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		if err = fmtutil.Render(os.Stdout, healthOutput, list); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+		unhealthy := 0
+		for i := range list {
+			if !list[i].Healthy() {
+				unhealthy++
+			}
+		}
+		if unhealthy > 0 {
+			print.FailureStatusEvent(os.Stderr, "%d of %d control plane components are unhealthy", unhealthy, len(list))
+			os.Exit(kubernetes.ExitError)
+		}
+		print.SuccessStatusEvent(os.Stderr, "All %d control plane components are healthy", len(list))
+	},
+}
+
+func init() {
+	HealthCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	HealthCmd.Flags().StringVarP(&healthOutput, "output", "o", "", "The output format. Valid values are: json, yaml, csv, or table (default)")
+	HealthCmd.Flags().StringArrayVarP(&healthComponents, "component", "", []string{}, "A component to check, can be repeated, all of them are checked if not set")
+	HealthCmd.RegisterFlagCompletionFunc("component", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return kubernetes.ControlPlaneComponents(), cobra.ShellCompDirectiveNoFileComp
+	})
+	HealthCmd.Flags().DurationVarP(&healthTimeout, "timeout", "", kubernetes.HealthTimeout, "How long to wait for each component to answer")
+	HealthCmd.Flags().IntVarP(&healthConcurrency, "concurrency", "", kubernetes.HealthConcurrency, "The number of components checked at once")
+	RootCmd.AddCommand(HealthCmd)
+}
@@ -17,47 +17,248 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/tkeel-io/cli/fileutil"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/utils"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/jsonpath"
 
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
-const defaultHTTPVerb = http.MethodPost
+const defaultInvokeTimeout = 30 * time.Second
 
 var (
-	invokeAppID     string
-	invokeAppMethod string
-	invokeData      string
-	invokeVerb      string
-	invokeDataFile  string
+	invokeAppID       string
+	invokeAppMethod   string
+	invokeData        string
+	invokeVerb        string
+	invokeDataFile    string
+	invokeTimeout     time.Duration
+	invokeContentType string
+	invokeAccept      string
+	invokeHeaders     []string
+	invokeParams      []string
+	invokeArgs        []string
+	invokeOutputFile  string
+	invokeRetries     int
+	invokeRetryOn     []int
+	invokeAddress     string
+	invokeAllowAll    bool
+	invokeLocalPort   int
+	invokeProtocol    string
+	invokeTLS         bool
+	invokeInsecure    bool
+	invokePod         string
+	invokePatchType   string
+	invokeInclude     bool
+	invokeCert        string
+	invokeKey         string
+	invokeCACert      string
+	invokeServerName  string
+	invokeNoAuth      bool
+	invokeDryRun      bool
+	invokeKeepAlive   bool
+	invokeIdleTimeout time.Duration
+	invokeWaitReady   time.Duration
+	invokeHTTP2       bool
+	invokeTrace       bool
+	invokeStream      bool
+	invokeBatch       bool
+	invokeConcurrency int
+	invokeRate        float64
+	invokeStopOnError bool
+	invokeByService   bool
+	invokePrintURL    bool
+	invokeIfNoneMatch string
+	invokeIfMatch     string
+	invokeJSONPath    string
+	invokeExpandEnv   bool
+	invokeAllowEmpty  bool
 )
 
 var InvokeCmd = &cobra.Command{
 	Use:   "invoke",
 	Short: "Invoke a method on a given tKeel plugin(application).",
+	Long: `Invoke a method on a given tKeel plugin(application).
+
+Without --verb the request is sent as POST when a body is given with --data or
+--data-file, and as GET otherwise. An explicit --verb always wins.
+
+Once the response is read, its status, size and duration are printed on
+stderr, --quiet leaves them out.
+
+With --if-none-match, a 304 Not Modified response prints nothing and exits 0,
+so that scripts can poll an endpoint cheaply. --include shows the ETag of the
+responses to pass back.
+
+` + kubernetes.ExitCodeHelp,
 	Example: `
-# Invoke a sample method on target app with POST Verb
-tkeel invoke --plugin-id target --method v1/sample --dao '{"key":"value"}
+# Invoke a sample method on target app with POST Verb, implied by the payload
+tkeel invoke --plugin-id target --method v1/sample --data '{"key":"value"}'
+
+# Invoke a sample method on target app with GET Verb, implied by the missing payload
+tkeel invoke --plugin-id target --method v1/sample
+
+# Invoke a sample method on target app with the payload read from a file
+tkeel invoke --plugin-id target --method v1/sample --data-file payload.json
+
+# Invoke a sample method on target app with the payload read from stdin
+cat payload.json | tkeel invoke --plugin-id target --method v1/sample --data-file -
+
+# Invoke a sample method on target app with a form encoded payload
+tkeel invoke --plugin-id target --method v1/sample --data 'key=value' --content-type application/x-www-form-urlencoded
+
+# Invoke a sample method on target app asking for a YAML response
+tkeel invoke --plugin-id target --method v1/sample --verb GET --accept application/yaml
+
+# Invoke a sample method on target app with custom headers
+tkeel invoke --plugin-id target --method v1/sample --verb GET -H "Authorization: Bearer token" -H "X-Trace: abc"
+
+# Invoke a sample method on target app with query parameters
+tkeel invoke --plugin-id target --method v1/sample --verb GET --param pageNum=1 --param pageSize=20
+
+# Invoke a method of target app with the {id} placeholder of its path filled in
+tkeel invoke --plugin-id target --method 'devices/{id}/status' --arg id=abc123
+
+# Invoke a sample method on target app and save the response to a file
+tkeel invoke --plugin-id target --method v1/export --verb GET -o ./export/data.json
+
+# Invoke a sample method on target app, retrying up to 3 times on transient failures
+tkeel invoke --plugin-id target --method v1/sample --verb GET --retries 3 --verbose
+
+# Invoke a sample method on target app, printing the request and response headers
+tkeel invoke --plugin-id target --method v1/sample --verb GET -VV
+
+# Invoke a sample method on target app, printing the equivalent curl command and the timing of the response
+tkeel invoke --plugin-id target --method v1/sample --verb GET --trace
+
+# Invoke a sample method on target app, printing the response status line and headers before the body
+tkeel invoke --plugin-id target --method v1/sample --verb GET -i
 
 # Invoke a sample method on target app with GET Verb
 tkeel invoke --plugin-id target --method v1/sample --verb GET
+
+# Invoke a sample method on target app, printing nothing but the response body
+tkeel invoke --plugin-id target --method v1/sample --verb GET -q | jq .
+
+# Invoke a sample method on target app through the dapr gRPC API
+tkeel invoke --plugin-id target --method v1/sample --data '{"key":"value"}' --protocol grpc
+
+# Invoke a sample method on target app serving HTTP/2 in cleartext (h2c)
+tkeel invoke --plugin-id target --method v1/sample --verb GET --http2
+
+# Invoke a rate limited method on target app, retrying only on 429 and 503 after the delay it asks for
+tkeel invoke --plugin-id target --method v1/sample --verb GET --retries 5 --retry-on 429,503
+
+# Invoke a sample method on target app, opening a new connection for every retry
+tkeel invoke --plugin-id target --method v1/sample --verb GET --retries 3 --keepalive=false
+
+# Invoke a sample method on target app that terminates TLS itself
+tkeel invoke --plugin-id target --method v1/sample --verb GET --tls --insecure
+
+# Invoke a sample method on target app that requires a client certificate (mTLS)
+tkeel invoke --plugin-id target --method v1/sample --verb GET --cert client.crt --key client.key --insecure
+
+# Print the URL a sample method of target app is invoked at, to call it with curl
+tkeel invoke --plugin-id target --method v1/sample --param page=2 --print-url
+
+# Print the request that would update target app, without sending it
+tkeel invoke --plugin-id target --method v1/config --verb PUT --data '{"level":"debug"}' --dry-run
+
+# Invoke a sample method on target app without the token cached by tkeel auth login
+tkeel invoke --plugin-id target --method v1/sample --verb GET --no-auth
+
+# Invoke a sample method on a specific replica of target app
+tkeel invoke --plugin-id target --method v1/sample --verb GET --pod target-5d8f7c9b4-x2kqz
+
+# Invoke a sample method on target app right after deploying it, waiting up to 2 minutes for a ready pod
+tkeel invoke --plugin-id target --method v1/sample --verb GET --wait-ready 2m
+
+# Partially update the config of target app with a JSON merge patch
+tkeel invoke --plugin-id target --method v1/config --verb PATCH --patch-type merge --data '{"level":"debug"}'
+
+# Partially update the config of target app with a JSON patch
+tkeel invoke --plugin-id target --method v1/config --verb PATCH --patch-type json --data '[{"op":"replace","path":"/level","value":"debug"}]'
+
+# Invoke a sample method on target app and give up after 10 seconds
+tkeel invoke --plugin-id target --method v1/sample --verb GET --timeout 10s
+
+# Load records into target app, one POST per JSON object line of the file, 4 at a time
+tkeel invoke --plugin-id target --method v1/records --batch --concurrency 4 < records.ndjson
+
+# Load records into a fragile plugin, sending no more than 5 requests per second
+tkeel invoke --plugin-id target --method v1/records --batch --concurrency 4 --rate 5 < records.ndjson
+
+# Call the healthz path of port 8080 of the nginx service in the default namespace, without dapr
+tkeel invoke --by-service --plugin-id default/nginx:8080 --method healthz --verb GET
+
+# Fetch the config of target app only when it changed since the ETag of the last response
+tkeel invoke --plugin-id target --method v1/config --if-none-match '"33a64df5"' --include
+
+# Fill the ${DEVICE_ID} and ${TOKEN} references of a payload template from the environment
+tkeel invoke --plugin-id target --method v1/devices --data-file device.json.tpl --expand-env
+
+# Print only the ids of the devices listed by target app
+tkeel invoke --plugin-id target --method v1/devices --jsonpath '{.items[*].id}'
+
+# Follow the server-sent events of target app as they arrive, until Ctrl+C
+tkeel invoke --plugin-id target --method v1/events --verb GET --stream
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		bytePayload := []byte{}
 		var err error
 		if invokeDataFile != "" && invokeData != "" {
-			print.FailureStatusEvent(os.Stdout, "Only one of --dao and --dao-file allowed in the same invoke command")
-			os.Exit(1)
+			print.FailureStatusEvent(os.Stdout, "--data and --data-file are mutually exclusive, only one of them is allowed in the same invoke command")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if invokeStream && invokeInclude {
+			print.FailureStatusEvent(os.Stdout, "--include can't be used with --stream, the body is printed before the response is complete")
+			os.Exit(kubernetes.ExitUsage)
 		}
 
-		if invokeDataFile != "" {
+		if invokeBatch && (invokeData != "" || invokeDataFile != "" || invokeStream || invokeInclude || invokeDryRun || invokeOutputFile != "" || invokeJSONPath != "" || invokeExpandEnv) {
+			print.FailureStatusEvent(os.Stdout, "--batch reads the bodies from stdin and only reports the status of each request, it can't be used with --data, --data-file, --stream, --include, --dry-run, --output-file, --jsonpath or --expand-env")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if invokeStream && invokeJSONPath != "" {
+			print.FailureStatusEvent(os.Stdout, "--jsonpath can't be used with --stream, the body is printed before the response is complete")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		var jsonPath *jsonpath.JSONPath
+		if invokeJSONPath != "" {
+			if jsonPath, err = utils.ParseJSONPath(invokeJSONPath); err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(kubernetes.ExitUsage)
+			}
+		}
+		if invokeConcurrency < 1 {
+			print.FailureStatusEvent(os.Stdout, "--concurrency must be at least 1")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if invokeRate != 0 && !invokeBatch {
+			print.FailureStatusEvent(os.Stdout, "--rate only applies with --batch")
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		if invokeDataFile == "-" {
+			bytePayload, err = ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error reading payload from stdin. Error: %s", err)
+				os.Exit(1)
+			}
+		} else if invokeDataFile != "" {
 			bytePayload, err = ioutil.ReadFile(invokeDataFile)
 			if err != nil {
 				print.FailureStatusEvent(os.Stdout, "Error reading payload from '%s'. Error: %s", invokeDataFile, err)
@@ -66,15 +267,184 @@ tkeel invoke --plugin-id target --method v1/sample --verb GET
 		} else if invokeData != "" {
 			bytePayload = []byte(invokeData)
 		}
+		if invokeAllowEmpty && !invokeExpandEnv {
+			print.FailureStatusEvent(os.Stdout, "--expand-env-allow-empty only applies with --expand-env")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if invokeExpandEnv {
+			if bytePayload, err = utils.ExpandEnv(bytePayload, os.LookupEnv, invokeAllowEmpty); err != nil {
+				print.FailureStatusEvent(os.Stdout, "%s, export it or pass --expand-env-allow-empty", err)
+				os.Exit(kubernetes.ExitUsage)
+			}
+		}
+
+		header, err := utils.ParseHeaders(invokeHeaders)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		if err = kubernetes.ValidateAddress(invokeAddress, invokeAllowAll); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		if invokeIfNoneMatch != "" {
+			header.Set("If-None-Match", utils.QuoteETag(invokeIfNoneMatch))
+		}
+		if invokeIfMatch != "" {
+			header.Set("If-Match", utils.QuoteETag(invokeIfMatch))
+		}
+
+		// the token of tKeel is not handed to services outside of it.
+		if !invokeNoAuth && !invokeByService && header.Get("Authorization") == "" {
+			token, err := kubernetes.CurrentToken()
+			switch {
+			case err == nil:
+				header.Set("Authorization", "Bearer "+token)
+			case errors.Is(err, kubernetes.ErrNotLoggedIn):
+				// plugins without auth can be invoked without a login.
+			default:
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(kubernetes.ExitCode(err))
+			}
+		}
 
-		response, err := kubernetes.InvokeByPortForward(invokeAppID, invokeAppMethod, bytePayload, invokeVerb)
+		params, err := utils.ParseParams(invokeParams)
 		if err != nil {
-			err = fmt.Errorf("error invoking plugin %s: %w", invokeAppID, err)
 			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		pathArgs, err := utils.ParseParams(invokeArgs)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, "Invalid --arg: %s", err)
+			os.Exit(kubernetes.ExitUsage)
+		}
+		method, err := utils.ExpandPath(invokeAppMethod, pathArgs)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, "%s, set it with --arg name=value", err)
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		invoker := &kubernetes.Invoker{
+			Timeout:          invokeTimeout,
+			ContentType:      invokeContentType,
+			Accept:           invokeAccept,
+			Header:           header,
+			Params:           params,
+			Retries:          invokeRetries,
+			RetryOn:          invokeRetryOn,
+			Address:          invokeAddress,
+			LocalPort:        invokeLocalPort,
+			Protocol:         invokeProtocol,
+			TLS:              invokeTLS,
+			Insecure:         invokeInsecure,
+			Pod:              invokePod,
+			WaitReady:        invokeWaitReady,
+			PatchType:        invokePatchType,
+			CertFile:         invokeCert,
+			KeyFile:          invokeKey,
+			CACertFile:       invokeCACert,
+			ServerName:       invokeServerName,
+			DisableKeepAlive: !invokeKeepAlive,
+			IdleConnTimeout:  invokeIdleTimeout,
+			HTTP2:            invokeHTTP2,
+		}
+		if invokeByService {
+			if invoker.Service, err = kubernetes.ParseServiceTarget(invokeAppID); err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(kubernetes.ExitUsage)
+			}
+		}
+		if invokeTrace {
+			invoker.Trace = os.Stderr
+		}
+		if invokeStream && invokeOutputFile != "" {
+			f, err := fileutil.LocateFile(fileutil.RewriteFlag(), invokeOutputFile)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error writing response to '%s'. Error: %s", invokeOutputFile, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			invoker.Stream = f
+		} else if invokeStream {
+			invoker.Stream = os.Stdout
+		}
+		verb := invokeVerb
+		if verb == "" {
+			verb = defaultVerb(invokeData != "" || invokeDataFile != "" || invokeBatch)
+		}
+		if invokeBatch {
+			invokeBatchStdin(invoker, method, verb)
+			return
+		}
+		if invokePrintURL {
+			u, forward, err := invoker.InvokeURL(invokeAppID, method)
+			if err != nil {
+				failPlugin(invokeAppID, err)
+			}
+			fmt.Println(u)
+			print.InfoStatusEvent(os.Stderr, "Forward the port it points at with: %s", forward)
+			return
+		}
+		if invokeDryRun {
+			req, err := invoker.DryRun(invokeAppID, method, bytePayload, verb)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(kubernetes.ExitCode(err))
+			}
+			if invokeByService {
+				print.InfoStatusEvent(os.Stdout, "Dry run, service %s would be invoked with:", invoker.Service)
+			} else {
+				print.InfoStatusEvent(os.Stdout, "Dry run, plugin %s would be invoked through its dapr sidecar with:", invokeAppID)
+			}
+			fmt.Print(req)
+			return
+		}
+		res, err := invoker.InvokeByPortForwardResult(invokeAppID, method, bytePayload, verb)
+		if err != nil {
+			if invokeInclude && res != nil && res.Status != "" {
+				fmt.Print(formatResponseHead(res.Status, res.Header))
+			}
+			if res != nil && res.Status != "" {
+				printInvokeSummary(res)
+			}
+			failPlugin(invokeAppID, fmt.Errorf("error invoking plugin %s: %w", invokeAppID, err))
+		}
+
+		if res.StatusCode == http.StatusNotModified {
+			// what the caller has is still current, there is nothing to print.
+			if invokeInclude {
+				fmt.Print(formatResponseHead(res.Status, res.Header))
+			}
+			return
+		}
+
+		printInvokeSummary(res)
+		if invokeStream {
+			print.SuccessStatusEvent(os.Stdout, "Stream of plugin %s ended", invokeAppID)
+			return
+		}
+
+		response := res.Body
+		if jsonPath != nil {
+			if response, err = utils.ExecuteJSONPath(jsonPath, []byte(res.Body)); err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error filtering the response of plugin %s with --jsonpath: %s", invokeAppID, err)
+				os.Exit(kubernetes.ExitError)
+			}
+		}
+		if invokeInclude && res.Status != "" {
+			response = formatResponseHead(res.Status, res.Header) + response
 		}
 
-		if response != "" {
+		if invokeOutputFile != "" {
+			if err = writeInvokeResponse(invokeOutputFile, response); err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error writing response to '%s'. Error: %s", invokeOutputFile, err)
+				os.Exit(1)
+			}
+			print.InfoStatusEvent(os.Stdout, "Wrote %d bytes to %s", len(response), invokeOutputFile)
+		} else if response != "" {
 			fmt.Println(response)
 		}
 
@@ -82,12 +452,125 @@ tkeel invoke --plugin-id target --method v1/sample --verb GET
 	},
 }
 
+// failPlugin reports a failure to reach the plugin, with advice for the
+// causes the user can fix, and exits with the matching exit code.
+func failPlugin(pluginID string, err error) {
+	print.FailureStatusEvent(os.Stdout, err.Error())
+	switch {
+	case errors.Is(err, kubernetes.ErrKubeConfig):
+		print.InfoStatusEvent(os.Stdout, "Check the access to the cluster, see --kubeconfig and --context")
+	case errors.Is(err, kubernetes.ErrAppNotFound):
+		print.InfoStatusEvent(os.Stdout, "Check the plugin id, tkeel plugin list shows the installed plugins")
+	case errors.Is(err, kubernetes.ErrPodNotRunning):
+		print.InfoStatusEvent(os.Stdout, "Check the pods of the plugin with tkeel plugin status %s", pluginID)
+	case errors.Is(err, kubernetes.ErrServiceNotReady):
+		print.InfoStatusEvent(os.Stdout, "Check the pods behind the service with kubectl get endpoints")
+	}
+	os.Exit(kubernetes.ExitCode(err))
+}
+
+// printInvokeSummary reports the status, body size and duration of the
+// response on stderr, it is left out with --quiet.
+func printInvokeSummary(res *kubernetes.InvokeResult) {
+	status := res.Status
+	if status == "" {
+		// gRPC responses have no status line.
+		status = "Response"
+	}
+	print.InfoStatusEvent(os.Stderr, "%s, %d bytes in %s", status, res.Size, res.Duration.Round(time.Microsecond))
+}
+
+// defaultVerb picks the verb of an invoke without --verb: POST when it
+// carries a body, GET otherwise.
+func defaultVerb(hasBody bool) string {
+	if hasBody {
+		return http.MethodPost
+	}
+	return http.MethodGet
+}
+
+// formatResponseHead renders the status line and headers the way curl -i
+// does, with a blank line separating them from the body.
+func formatResponseHead(status string, header http.Header) string {
+	var b strings.Builder
+	b.WriteString(status + "\n")
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			fmt.Fprintf(&b, "%s: %s\n", k, v)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func writeInvokeResponse(path, response string) error {
+	f, err := fileutil.LocateFile(fileutil.RewriteFlag(), path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(response)
+	return err
+}
+
 func init() {
 	InvokeCmd.Flags().StringVarP(&invokeAppID, "plugin-id", "p", "", "The application id to invoke")
+	InvokeCmd.RegisterFlagCompletionFunc("plugin-id", completion.Plugins)
 	InvokeCmd.Flags().StringVarP(&invokeAppMethod, "method", "m", "", "The method to invoke")
-	InvokeCmd.Flags().StringVarP(&invokeData, "dao", "d", "", "The JSON serialized dao string (optional)")
-	InvokeCmd.Flags().StringVarP(&invokeVerb, "verb", "v", defaultHTTPVerb, "The HTTP verb to use")
-	InvokeCmd.Flags().StringVarP(&invokeDataFile, "dao-file", "f", "", "A file containing the JSON serialized dao (optional)")
+	InvokeCmd.Flags().StringVarP(&invokeData, "data", "d", "", "The JSON serialized data string (optional)")
+	InvokeCmd.Flags().StringVarP(&invokeVerb, "verb", "v", "", "The HTTP verb to use, defaults to POST with a body and GET without")
+	InvokeCmd.Flags().StringVarP(&invokeDataFile, "data-file", "f", "", "A file containing the JSON serialized data, use - to read from stdin (optional)")
+	InvokeCmd.Flags().StringVar(&invokeData, "dao", "", "The JSON serialized data string (optional)")
+	InvokeCmd.Flags().StringVar(&invokeDataFile, "dao-file", "", "A file containing the JSON serialized data (optional)")
+	InvokeCmd.Flags().MarkDeprecated("dao", "use --data instead")
+	InvokeCmd.Flags().MarkDeprecated("dao-file", "use --data-file instead")
+	InvokeCmd.Flags().StringVarP(&invokeContentType, "content-type", "", kubernetes.DefaultContentType, "The Content-Type of the request payload")
+	InvokeCmd.Flags().StringVarP(&invokeAccept, "accept", "", "", "The Accept header of the request, the response format to ask the plugin for")
+	InvokeCmd.Flags().StringArrayVarP(&invokeHeaders, "header", "H", []string{}, "A 'Key: Value' header to add to the request, can be repeated")
+	InvokeCmd.Flags().StringArrayVarP(&invokeParams, "param", "", []string{}, "A 'key=value' query parameter to add to the method, can be repeated")
+	InvokeCmd.Flags().StringArrayVarP(&invokeArgs, "arg", "", []string{}, "A 'name=value' substitution for a {name} placeholder of the method, can be repeated")
+	InvokeCmd.Flags().StringVarP(&invokeOutputFile, "output-file", "o", "", "Write the response body to this file instead of stdout")
+	InvokeCmd.Flags().IntVarP(&invokeRetries, "retries", "", 0, "How many times to retry the invoke on transient failures")
+	InvokeCmd.Flags().IntSliceVarP(&invokeRetryOn, "retry-on", "", nil, "Comma separated response status codes to retry on with --retries instead of any 5xx, which is never retried for POST and PATCH unless listed here. A Retry-After header sets the delay before the next attempt, up to 10s")
+	InvokeCmd.Flags().StringVarP(&invokeAddress, "address", "", kubernetes.DefaultAddress, "Comma separated local addresses the port-forward listens on")
+	InvokeCmd.Flags().BoolVarP(&invokeAllowAll, "allow-all", "", false, "Allow the port-forward to listen on all interfaces (0.0.0.0)")
+	InvokeCmd.Flags().IntVarP(&invokeLocalPort, "local-port", "", 0, "The local port the port-forward listens on, 0 picks a random port")
+	InvokeCmd.Flags().StringVarP(&invokeProtocol, "protocol", "", kubernetes.ProtocolHTTP, "The protocol used to invoke the plugin through dapr. Valid values are: http or grpc")
+	InvokeCmd.Flags().BoolVarP(&invokeTLS, "tls", "", false, "Use https to call the plugin through the port-forward")
+	InvokeCmd.Flags().BoolVarP(&invokeKeepAlive, "keepalive", "", true, "Reuse the connection to the plugin for the requests of a session or batch, --keepalive=false opens a new one for every request")
+	InvokeCmd.Flags().DurationVarP(&invokeIdleTimeout, "idle-conn-timeout", "", kubernetes.DefaultIdleConnTimeout, "How long an idle connection to the plugin is kept for reuse")
+	InvokeCmd.Flags().BoolVarP(&invokeHTTP2, "http2", "", false, "Use HTTP/2 in cleartext (h2c) to call the plugin, over --tls HTTP/2 is negotiated when the plugin supports it")
+	InvokeCmd.Flags().BoolVarP(&invokeInsecure, "insecure", "", false, "Skip verifying the certificate when --tls is set")
+	InvokeCmd.Flags().StringVarP(&invokeCert, "cert", "", "", "Client certificate file presented to the plugin over TLS, requires --key and implies --tls")
+	InvokeCmd.Flags().StringVarP(&invokeKey, "key", "", "", "Private key file of the --cert client certificate")
+	InvokeCmd.Flags().StringVarP(&invokeCACert, "cacert", "", "", "CA certificate file to verify the plugin certificate with, implies --tls. Set --tls-server-name to the name in the certificate")
+	InvokeCmd.Flags().StringVarP(&invokeServerName, "tls-server-name", "", "", "Name the plugin certificate is verified for instead of the port-forward address, e.g. <plugin>.<namespace>.svc, implies --tls")
+	InvokeCmd.Flags().BoolVarP(&invokePrintURL, "print-url", "", false, "Print the URL the request would be sent to through the port-forward, on --local-port or the dapr HTTP port of the pod, and the kubectl command forwarding it, without sending it")
+	InvokeCmd.Flags().BoolVarP(&invokeDryRun, "dry-run", "", false, "Print the composed request including headers instead of sending it, whatever the verb")
+	InvokeCmd.Flags().BoolVarP(&invokeNoAuth, "no-auth", "", false, "Do not send the token cached by tkeel auth login as a bearer Authorization header")
+	InvokeCmd.Flags().StringVarP(&invokePod, "pod", "", "", "The pod of the plugin to invoke, the first running pod is used if empty")
+	InvokeCmd.Flags().DurationVarP(&invokeWaitReady, "wait-ready", "", 0, "How long to wait for a pod of the plugin to be running and ready before invoking, 0 fails right away when none is running")
+	InvokeCmd.Flags().StringVarP(&invokePatchType, "patch-type", "", "", "Send a PATCH body as a merge, json or strategic patch, setting its Content-Type. Valid values are: merge, json or strategic")
+	InvokeCmd.Flags().BoolVarP(&invokeTrace, "trace", "", false, "Print the equivalent curl command of the request and the connect, time to first byte and total time of the response on stderr (http protocol only)")
+	InvokeCmd.Flags().BoolVarP(&invokeStream, "stream", "", false, "Print the response body as it arrives until the plugin closes it or Ctrl+C, for chunked and server-sent event responses; --timeout then bounds the wait for the response headers (http protocol only)")
+	InvokeCmd.Flags().BoolVarP(&invokeBatch, "batch", "", false, "Read newline-delimited JSON objects from stdin and invoke the method once per line over a single port-forward, reporting the status of each line")
+	InvokeCmd.Flags().IntVarP(&invokeConcurrency, "concurrency", "", 1, "With --batch, how many requests are sent at the same time")
+	InvokeCmd.Flags().Float64VarP(&invokeRate, "rate", "", 0, "With --batch, the most requests sent per second whatever the --concurrency, 0 means no limit")
+	InvokeCmd.Flags().BoolVarP(&invokeStopOnError, "stop-on-error", "", false, "With --batch, stop reading stdin after the first failed line")
+	InvokeCmd.Flags().BoolVarP(&invokeByService, "by-service", "", false, "Take --plugin-id as a namespace/service:port kubernetes service and send the request to the --method path of one of its pods directly instead of through dapr, without the cached token")
+	InvokeCmd.Flags().StringVarP(&invokeIfNoneMatch, "if-none-match", "", "", "Send If-None-Match with this ETag, a 304 Not Modified response prints nothing and exits 0")
+	InvokeCmd.Flags().StringVarP(&invokeIfMatch, "if-match", "", "", "Send If-Match with this ETag, e.g. to update only what was read")
+	InvokeCmd.Flags().BoolVarP(&invokeExpandEnv, "expand-env", "", false, "Expand the ${VAR} references of --data or --data-file with the environment, failing on unset variables")
+	InvokeCmd.Flags().BoolVarP(&invokeAllowEmpty, "expand-env-allow-empty", "", false, "With --expand-env, expand unset variables to an empty string instead of failing")
+	InvokeCmd.Flags().StringVarP(&invokeJSONPath, "jsonpath", "", "", "Only print the values of the JSON response matched by this JSONPath template, e.g. '{.items[*].id}'")
+	InvokeCmd.Flags().BoolVarP(&invokeInclude, "include", "i", false, "Print the response status line and headers before the body (http protocol only)")
+	InvokeCmd.Flags().DurationVarP(&invokeTimeout, "timeout", "", defaultInvokeTimeout, "The timeout of the invoke request, 0 means no timeout")
 	InvokeCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	InvokeCmd.MarkFlagRequired("plugin-id")
 	InvokeCmd.MarkFlagRequired("method")
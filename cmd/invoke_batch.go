@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+// invokeBatchStdin invokes the method once for every JSON object line of
+// stdin over a single port-forward, at most --rate lines per second, reports
+// each line and exits with an error when any of them failed.
+func invokeBatchStdin(invoker *kubernetes.Invoker, method, verb string) {
+	limiter, err := kubernetes.NewRateLimiter(invokeRate)
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(kubernetes.ExitUsage)
+	}
+	session, err := invoker.NewInvokeSession(invokeAppID)
+	if err != nil {
+		failPlugin(invokeAppID, fmt.Errorf("error invoking plugin %s: %w", invokeAppID, err))
+	}
+
+	succeeded, failed, stopped := 0, 0, false
+	err = session.InvokeBatch(os.Stdin, method, verb, invokeConcurrency, limiter, func(r *kubernetes.BatchResult) bool {
+		if r.Err != nil {
+			failed++
+			print.FailureStatusEvent(os.Stdout, "Line %d: %s", r.Line, r.Err)
+			stopped = stopped || invokeStopOnError
+			return !invokeStopOnError
+		}
+		succeeded++
+		status := r.Result.Status
+		if status == "" {
+			status = "OK"
+		}
+		print.SuccessStatusEvent(os.Stdout, "Line %d: %s", r.Line, status)
+		return true
+	})
+	session.Close()
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+
+	total := succeeded + failed
+	switch {
+	case stopped:
+		print.FailureStatusEvent(os.Stdout, "Batch stopped on the first failure: %d of %d lines sent to plugin %s failed", failed, total, invokeAppID)
+		os.Exit(1)
+	case failed > 0:
+		print.FailureStatusEvent(os.Stdout, "%d of %d lines sent to plugin %s failed", failed, total, invokeAppID)
+		os.Exit(1)
+	}
+	print.SuccessStatusEvent(os.Stdout, "Plugin %s invoked with %d lines", invokeAppID, total)
+}
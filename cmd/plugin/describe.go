@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var describePod string
+
+var PluginDescribeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Show the resolved dapr app of the pods of a plugin.",
+	Long: `Show the resolved dapr app of the pods of a plugin: its namespace, pod, phase,
+the dapr HTTP and gRPC ports, the app port, the base URL of dapr service
+invocation and the kubectl command forwarding the port it is reachable on.
+The first pod listed is the one invoke and port-forward use without --pod.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Describe the pods of a plugin
+tkeel plugin describe <plugin-id>
+
+# Describe one pod of a plugin as JSON
+tkeel plugin describe <plugin-id> --pod <pod-name> -o json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin describe <plugin-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		list, err := kubernetes.DescribePlugin(args[0], describePod)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		outputList(list, len(list))
+	},
+}
+
+func init() {
+	PluginDescribeCmd.ValidArgsFunction = completion.PluginArg
+	PluginDescribeCmd.Flags().StringVarP(&describePod, "pod", "", "", "Only describe this pod of the plugin")
+	PluginDescribeCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginCmd.AddCommand(PluginDescribeCmd)
+}
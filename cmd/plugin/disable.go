@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
@@ -50,6 +51,8 @@ tkeel plugin disable <plugin-id> -t <tenant-id>
 
 func init() {
 	PluginDisableCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "tenant id")
+	PluginDisableCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
 	PluginDisableCmd.MarkFlagRequired("tenant")
+	PluginDisableCmd.ValidArgsFunction = completion.PluginArg
 	PluginCmd.AddCommand(PluginDisableCmd)
 }
@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
@@ -51,6 +52,8 @@ tkeel plugin enable <plugin-id> -t <tenant-id>
 
 func init() {
 	PluginEnableCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "tenant id")
+	PluginEnableCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
 	PluginEnableCmd.MarkFlagRequired("tenant")
+	PluginEnableCmd.ValidArgsFunction = completion.PluginArg
 	PluginCmd.AddCommand(PluginEnableCmd)
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	execContainer string
+	execPod       string
+	execStdin     bool
+	execTTY       bool
+)
+
+var PluginExecCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "Run a command in a pod of a plugin.",
+	Long: `Run a command in a pod of a plugin. The command follows the plugin id
+after --, and tkeel exits with the exit status of the command.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# List the files in the working directory of the app container of the plugin
+tkeel plugin exec <plugin-id> -- ls -la
+
+# Open an interactive shell in the plugin
+tkeel plugin exec <plugin-id> -it -- sh
+
+# Run a command in the dapr sidecar of a specific replica of the plugin
+tkeel plugin exec <plugin-id> --pod <pod-name> --container daprd -- env
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dash := cmd.ArgsLenAtDash()
+		if dash != 1 || len(args) < 2 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id and the command after --")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin exec <plugin-id> -- ls -la")
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		opts := kubernetes.ExecOptions{
+			Pod:       execPod,
+			Container: execContainer,
+			Stdin:     execStdin,
+			TTY:       execTTY,
+		}
+		err := kubernetes.PluginExec(args[0], args[dash:], opts)
+		if code, ok := kubernetes.ExecExitStatus(err); ok {
+			os.Exit(code)
+		}
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+	},
+}
+
+func init() {
+	PluginExecCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginExecCmd.Flags().StringVarP(&execContainer, "container", "", "", "The container to run the command in, defaults to the app container")
+	PluginExecCmd.Flags().StringVarP(&execPod, "pod", "", "", "The pod of the plugin to run the command in, the first running pod is used if empty")
+	PluginExecCmd.Flags().BoolVarP(&execStdin, "stdin", "i", false, "Pass stdin to the command")
+	PluginExecCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a terminal for the command, use with -i for an interactive shell")
+	PluginExecCmd.ValidArgsFunction = completion.PluginArg
+	PluginCmd.AddCommand(PluginExecCmd)
+}
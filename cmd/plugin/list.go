@@ -20,6 +20,9 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/config"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
@@ -30,23 +33,89 @@ var PluginStatusCmd = &cobra.Command{
 	Example: `
 # List the installed plugins 
 tkeel plugin list
+
+# List the pods of the installed plugins with their status and ports
+tkeel plugin list --pods
+
+# List the plugins whose pods carry a label
+tkeel plugin list -l app.kubernetes.io/part-of=tkeel
+
+# List the plugin pods that are not running
+tkeel plugin list --pods --field-selector status.phase!=Running
+
+# List the plugins of the clusters of every context of the kubeconfig
+tkeel plugin list --all-contexts
+
+# List the names and states of the plugins, one per line
+tkeel plugin list --output-template '{{.Name}} {{.Status | lower}}'
 `,
 	Run: func(cmd *cobra.Command, args []string) {
+		format, err := fmtutil.ResolveFormat(outputFormat, outputTemplate)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+		outputFormat = format
+		if allContexts {
+			// a tenant or context defaulted by the config file is left aside.
+			if pods || config.Typed(cmd, config.KeyTenant) || selector != "" || fieldSel != "" || config.Typed(cmd, config.KeyContext) {
+				print.FailureStatusEvent(os.Stdout, "--all-contexts lists the plugins of every cluster, it can't be used with --pods, --tenant, --selector, --field-selector or --context")
+				os.Exit(kubernetes.ExitUsage)
+			}
+			kubernetes.Context = ""
+			listAllContexts()
+			return
+		}
+
+		sel := kubernetes.PodSelector{Label: selector, Field: fieldSel}
+		if err := sel.Validate(); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		if pods {
+			list, err := kubernetes.ListPluginPodsSelected(sel)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "unable to list plugin pods:%s", err.Error())
+				os.Exit(1)
+			}
+			outputList(list, len(list))
+			os.Exit(0)
+		}
+
+		selected := selectedPlugins(sel)
+
 		if tenant != "" {
 			list, err := kubernetes.ListPluginsOfTenant(tenant)
 			if err != nil {
 				print.FailureStatusEvent(os.Stdout, "unable to list plugins:%s", err.Error())
 				os.Exit(1)
 			}
-			outputList(list, len(list))
+			filtered := list[:0]
+			for _, p := range list {
+				if selected(p.Name) {
+					filtered = append(filtered, p)
+				}
+			}
+			outputList(filtered, len(filtered))
 			os.Exit(0)
 		}
 
-		status, err := kubernetes.InstalledPlugin()
+		all, err := kubernetes.InstalledPlugin()
 		if err != nil {
 			print.FailureStatusEvent(os.Stdout, err.Error())
 			os.Exit(1)
 		}
+		status := all[:0]
+		for _, p := range all {
+			if selected(p.Name) {
+				status = append(status, p)
+			}
+		}
+		if len(status) == 0 && len(all) != 0 {
+			print.WarningStatusEvent(os.Stdout, "There is no plugin with pods matching the selectors.")
+			os.Exit(0)
+		}
 		if len(status) == 0 {
 			print.WarningStatusEvent(os.Stdout, "There is not plugin in your cluster.")
 			os.Exit(0)
@@ -56,8 +125,61 @@ tkeel plugin list
 	},
 }
 
+// listAllContexts lists the plugins of the cluster of every context of the
+// kubeconfig. The contexts that fail are reported without stopping the
+// others, and make the command exit 1.
+func listAllContexts() {
+	contexts, err := kubernetes.KubeContexts()
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(kubernetes.ExitCode(err))
+	}
+	if len(contexts) == 0 {
+		print.WarningStatusEvent(os.Stdout, "There is no context in your kubeconfig.")
+		os.Exit(0)
+	}
+
+	list, failures := kubernetes.ListPluginsOfContexts(contexts, kubernetes.ContextConcurrency)
+	for _, f := range failures {
+		print.WarningStatusEvent(os.Stderr, "unable to list plugins of %s", f)
+	}
+	if len(list) == 0 && len(failures) == 0 {
+		print.WarningStatusEvent(os.Stdout, "There is not plugin in your clusters.")
+		os.Exit(0)
+	}
+	if len(list) > 0 {
+		outputList(list, len(list))
+	}
+	if len(failures) > 0 {
+		os.Exit(kubernetes.ExitError)
+	}
+}
+
+// selectedPlugins returns whether a plugin has pods matching sel. The
+// plugins are listed by the platform, the selectors apply to their pods.
+func selectedPlugins(sel kubernetes.PodSelector) func(pluginID string) bool {
+	if sel == (kubernetes.PodSelector{}) {
+		return func(string) bool { return true }
+	}
+	list, err := kubernetes.ListPluginPodsSelected(sel)
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, "unable to list plugin pods:%s", err.Error())
+		os.Exit(1)
+	}
+	ids := make(map[string]bool, len(list))
+	for _, p := range list {
+		ids[p.ID] = true
+	}
+	return func(pluginID string) bool { return ids[pluginID] }
+}
+
 func init() {
 	PluginStatusCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	PluginStatusCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Show the plugin of this tenant")
+	PluginStatusCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	PluginStatusCmd.Flags().BoolVarP(&pods, "pods", "", false, "List the plugin pods running in the cluster instead")
+	PluginStatusCmd.Flags().StringVarP(&selector, "selector", "l", "", "Only list the plugins with pods matching this label selector, e.g. app.kubernetes.io/part-of=tkeel")
+	PluginStatusCmd.Flags().StringVarP(&fieldSel, "field-selector", "", "", "Only list the plugins with pods matching this field selector, e.g. status.phase=Running")
+	PluginStatusCmd.Flags().BoolVarP(&allContexts, "all-contexts", "", false, "List the plugins of the clusters of every context of the kubeconfig, with a CONTEXT column")
 	PluginCmd.AddCommand(PluginStatusCmd)
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	logsFollow    bool
+	logsTail      int64
+	logsContainer string
+	logsPod       string
+	logsSidecar   bool
+)
+
+var PluginLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print the logs of a plugin.",
+	Example: `
+# Print the logs of the app container of the plugin
+tkeel plugin logs <plugin-id>
+
+# Follow the last 100 lines of the logs of the plugin
+tkeel plugin logs <plugin-id> -f --tail 100
+
+# Follow the logs of the dapr sidecar of the plugin, to debug the routing of invokes
+tkeel plugin logs <plugin-id> --sidecar -f --tail 50
+
+# Print the logs of a specific replica of the plugin
+tkeel plugin logs <plugin-id> --pod <pod-name>
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin logs <plugin-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		if logsSidecar && logsContainer != "" {
+			print.FailureStatusEvent(os.Stdout, "--sidecar and --container are mutually exclusive")
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		opts := kubernetes.LogsOptions{
+			Pod:       logsPod,
+			Container: logsContainer,
+			Sidecar:   logsSidecar,
+			Follow:    logsFollow,
+			Tail:      logsTail,
+		}
+		if err := kubernetes.PluginLogs(ctx, args[0], opts, os.Stdout); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+	},
+}
+
+func init() {
+	PluginLogsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginLogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming the logs until interrupted")
+	PluginLogsCmd.Flags().Int64VarP(&logsTail, "tail", "", -1, "How many of the last lines to show, -1 shows all")
+	PluginLogsCmd.Flags().StringVarP(&logsContainer, "container", "", "", "The container to print the logs of, defaults to the app container")
+	PluginLogsCmd.Flags().BoolVarP(&logsSidecar, "sidecar", "", false, "Print the logs of the dapr sidecar container instead of the app container")
+	PluginLogsCmd.Flags().StringVarP(&logsPod, "pod", "", "", "The pod of the plugin to print the logs of, the first running pod is used if empty")
+	PluginLogsCmd.ValidArgsFunction = completion.PluginArg
+	PluginCmd.AddCommand(PluginLogsCmd)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var metadataPod string
+
+var PluginMetadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Show the components and subscriptions registered with the dapr sidecar of a plugin.",
+	Long: `Show the components, pub/sub subscriptions and actors registered with the
+dapr sidecar of a plugin, fetched from its metadata endpoint through a
+port-forward. -o json and -o yaml print the whole metadata.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# List the components and subscriptions of the dapr sidecar of a plugin
+tkeel plugin metadata <plugin-id>
+
+# Print the whole metadata of one pod of a plugin as JSON
+tkeel plugin metadata <plugin-id> --pod <pod-name> -o json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin metadata <plugin-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		pluginID := args[0]
+		metadata, err := kubernetes.PluginMetadata(pluginID, metadataPod)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+
+		if outputFormat == fmtutil.FormatJSON || outputFormat == fmtutil.FormatYAML {
+			outputList(metadata, 1)
+			return
+		}
+		entries := metadata.Entries()
+		if len(entries) == 0 {
+			print.WarningStatusEvent(os.Stdout, "No component, subscription or actor is registered with the dapr sidecar of %s", pluginID)
+			return
+		}
+		print.InfoStatusEvent(os.Stderr, "Dapr runtime %s of %s", metadata.RuntimeVersion, metadata.ID)
+		outputList(entries, len(entries))
+	},
+}
+
+func init() {
+	PluginMetadataCmd.ValidArgsFunction = completion.PluginArg
+	PluginMetadataCmd.Flags().StringVarP(&metadataPod, "pod", "", "", "The pod of the plugin to fetch the metadata of, the first running pod is used if empty")
+	PluginMetadataCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginCmd.AddCommand(PluginMetadataCmd)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	metricsPod  string
+	metricsPort int
+	metricsGrep string
+)
+
+var PluginMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Fetch the Prometheus metrics of the dapr sidecar of a plugin.",
+	Long: `Fetch the Prometheus metrics of the dapr sidecar of a plugin through a
+port-forward to its metrics port, the --metrics-port the sidecar was started
+with or 9090, and print them in the Prometheus text format.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Print all the metrics of the dapr sidecar of a plugin
+tkeel plugin metrics <plugin-id>
+
+# Print the latency metrics of the HTTP server of one pod of a plugin
+tkeel plugin metrics <plugin-id> --pod <pod-name> --grep 'dapr_http_server_.*latency'
+
+# Fetch the metrics from a sidecar serving them on a custom port
+tkeel plugin metrics <plugin-id> --metrics-port 9095
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin metrics <plugin-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		var re *regexp.Regexp
+		if metricsGrep != "" {
+			var err error
+			if re, err = regexp.Compile(metricsGrep); err != nil {
+				print.FailureStatusEvent(os.Stdout, "Invalid --grep: %s", err)
+				os.Exit(kubernetes.ExitUsage)
+			}
+		}
+
+		metrics, err := kubernetes.PluginMetrics(args[0], metricsPod, metricsPort)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		if re != nil {
+			if metrics = kubernetes.FilterMetrics(metrics, re); metrics == "" {
+				print.FailureStatusEvent(os.Stdout, "No metric of plugin %s matches %q", args[0], metricsGrep)
+				os.Exit(kubernetes.ExitNotFound)
+			}
+		}
+		fmt.Print(metrics)
+	},
+}
+
+func init() {
+	PluginMetricsCmd.ValidArgsFunction = completion.PluginArg
+	PluginMetricsCmd.Flags().StringVarP(&metricsPod, "pod", "", "", "The pod of the plugin to fetch the metrics of, the first running pod is used if empty")
+	PluginMetricsCmd.Flags().IntVarP(&metricsPort, "metrics-port", "", 0, "The metrics port of the dapr sidecar, resolved from the sidecar arguments if 0")
+	PluginMetricsCmd.Flags().StringVarP(&metricsGrep, "grep", "", "", "Only print the metrics whose name matches this regular expression")
+	PluginMetricsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginCmd.AddCommand(PluginMetricsCmd)
+}
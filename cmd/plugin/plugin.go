@@ -44,18 +44,24 @@ package plugin
 import (
 	"os"
 
-	"github.com/dapr/cli/utils"
-	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
 var PluginHelpExample = `
 tkeel plugin list
+tkeel plugin list --pods
+tkeel plugin list --all-contexts
 tkeel plugin install <repo-name>/<installer-id> <plugin-id>
 tkeel plugin install <repo-name>/<installer-id>@<version> <plugin-id>
 tkeel plugin uninstall <plugin-id>
 tkeel plugin show <plugin-id>
+tkeel plugin status <plugin-id>
+tkeel plugin describe <plugin-id>
+tkeel plugin metrics <plugin-id>
+tkeel plugin metadata <plugin-id>
+tkeel plugin restart <plugin-id>
 tkeel plugin enable <plugin-id> -t <tenant-id>
 tkeel plugin disable <plugin-id> -t <tenant-id>
 tkeel plugin upgrade <repo-name>/<installer-id> <plugin-id>
@@ -72,30 +78,20 @@ var PluginCmd = &cobra.Command{
 }
 
 func init() {
-	PluginCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "The output format of the list. Valid values are: json, yaml, or table (default)")
+	PluginCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "The output format of the list. Valid values are: json, yaml, csv, go-template=<template>, or table (default)")
+	PluginCmd.PersistentFlags().StringVarP(&outputTemplate, "output-template", "", "", "Render each item of the list with this Go template, e.g. '{{.Name}}'. The helpers upper, lower, join, default and json are available")
 	PluginCmd.PersistentFlags().BoolP("help", "h", false, "Print this help message")
 }
 
 func outputList(list interface{}, length int) {
-	if outputFormat == "json" || outputFormat == "yaml" {
-		err := utils.PrintDetail(os.Stdout, outputFormat, list)
-		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
-		}
-	} else {
-		table, err := gocsv.MarshalString(list)
-		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
-		}
-
-		// Standalone mode displays a separate message when no instances are found.
-		if length == 0 {
-			print.FailureStatusEvent(os.Stdout, "No Dapr instances found.")
-			os.Exit(1)
-		}
+	// Standalone mode displays a separate message when no instances are found.
+	if (outputFormat == "" || outputFormat == fmtutil.FormatTable) && length == 0 {
+		print.FailureStatusEvent(os.Stdout, "No Dapr instances found.")
+		os.Exit(1)
+	}
 
-		utils.PrintTable(table)
+	if err := fmtutil.Render(os.Stdout, outputFormat, list); err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var restartWait bool
+
+var PluginRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the pods of a plugin.",
+	Long: `Restart the pods of a plugin with a rollout restart of its deployment, like
+kubectl rollout restart: new pods replace the old ones as they become ready.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Restart a stuck plugin
+tkeel plugin restart <plugin-id>
+
+# Restart a plugin and wait up to 5 minutes for its new pods to be ready
+tkeel plugin restart <plugin-id> --wait --timeout 5m
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin restart <plugin-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		pluginID := args[0]
+
+		restart, err := kubernetes.RestartPlugin(pluginID)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		print.InfoStatusEvent(os.Stdout, "Restarting deployment %s, old pods: %s", strings.Join(restart.Deployments, ", "), strings.Join(restart.OldPods, ", "))
+		if !restartWait {
+			print.SuccessStatusEvent(os.Stdout, "Restart of plugin %s started, follow it with tkeel plugin status %s --watch", pluginID, pluginID)
+			return
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		pods, err := restart.Wait(ctx)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		print.SuccessStatusEvent(os.Stdout, "Plugin %s restarted, new pods: %s", pluginID, strings.Join(pods, ", "))
+	},
+}
+
+func init() {
+	PluginRestartCmd.Flags().BoolVarP(&restartWait, "wait", "", false, "Wait for the new pods of the plugin to be ready")
+	PluginRestartCmd.Flags().DurationVarP(&timeout, "timeout", "", 0, "How long --wait waits for the new pods, 0 means no timeout")
+	PluginRestartCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginRestartCmd.ValidArgsFunction = completion.PluginArg
+	PluginCmd.AddCommand(PluginRestartCmd)
+}
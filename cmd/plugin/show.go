@@ -19,6 +19,7 @@ package plugin
 import (
 	"os"
 
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 
 	"github.com/spf13/cobra"
@@ -55,5 +56,6 @@ tkeel plugin show <plugin-id>
 }
 
 func init() {
+	PluginInfoCmd.ValidArgsFunction = completion.PluginArg
 	PluginCmd.AddCommand(PluginInfoCmd)
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var PluginPodStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the health of the pod of a plugin.",
+	Example: `
+# Show pod phase, readiness, restarts and ports of the plugin
+tkeel plugin status <plugin-id>
+
+# Use it as a health check in scripts, it exits non-zero when the plugin is not running
+tkeel plugin status <plugin-id> -o json
+
+# Watch the pod of the plugin until it is running and ready
+tkeel plugin status <plugin-id> --watch
+
+# Fail if the plugin is not ready within 2 minutes
+tkeel plugin status <plugin-id> --watch --timeout 2m
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel plugin status <plugin-id>")
+			os.Exit(1)
+		}
+		pluginID := args[0]
+
+		if watch {
+			watchPluginStatus(pluginID)
+			return
+		}
+
+		status, err := kubernetes.PluginPodStatus(pluginID)
+		if err != nil {
+			if errors.Is(err, kubernetes.ErrPodNotRunning) {
+				print.FailureStatusEvent(os.Stdout, "Plugin %s is not running", pluginID)
+				os.Exit(1)
+			}
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+
+		outputList([]*kubernetes.PluginPodStatusOutput{status}, 1)
+	},
+}
+
+// watchPluginStatus prints the status of the plugin on every change of its
+// pods until it is running and ready, interrupted, or --timeout passes.
+func watchPluginStatus(pluginID string) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := kubernetes.WatchPluginPodStatus(ctx, pluginID, func(s *kubernetes.PluginPodStatusOutput) {
+		print.InfoStatusEvent(os.Stdout, "%s: %s, ready %s, restarts %d", s.PodName, s.Phase, s.Ready, s.Restarts)
+	})
+	switch {
+	case err == nil:
+		print.SuccessStatusEvent(os.Stdout, "Plugin %s is running and ready", pluginID)
+	case errors.Is(err, context.DeadlineExceeded):
+		print.FailureStatusEvent(os.Stdout, "Plugin %s is not ready after %s", pluginID, timeout)
+		os.Exit(1)
+	case errors.Is(err, context.Canceled):
+		os.Exit(1)
+	default:
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	PluginPodStatusCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PluginPodStatusCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch the pods of the plugin until one is running and ready")
+	PluginPodStatusCmd.Flags().DurationVarP(&timeout, "timeout", "", 0, "How long --watch waits for the plugin to become ready, 0 means no timeout")
+	PluginPodStatusCmd.ValidArgsFunction = completion.PluginArg
+	PluginCmd.AddCommand(PluginPodStatusCmd)
+}
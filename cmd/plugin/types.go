@@ -16,8 +16,18 @@ limitations under the License.
 
 package plugin
 
+import "time"
+
 var (
-	outputFormat string
-	tenant       string
-	force        bool
+	outputFormat   string
+	outputTemplate string
+	tenant         string
+	force          bool
+	pods           bool
+	selector       string
+	fieldSel       string
+	watch          bool
+	timeout        time.Duration
+	dryRun         bool
+	allContexts    bool
 )
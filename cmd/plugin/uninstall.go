@@ -1,10 +1,12 @@
 package plugin
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
@@ -15,6 +17,9 @@ var PluginUninstallCmd = &cobra.Command{
 	Example: `
 # Uninstall the specified plugin by id
 tkeel plugin uninstall <plugin-id>
+
+# Show the tenants using the plugin and the requests uninstalling it would send
+tkeel plugin uninstall <plugin-id> --force --dry-run
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
@@ -23,6 +28,18 @@ tkeel plugin uninstall <plugin-id>
 			os.Exit(1)
 		}
 		pluginID := args[0]
+		if dryRun {
+			tenants, reqs, err := kubernetes.DryRunUninstallPlugin(pluginID, force)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			print.InfoStatusEvent(os.Stdout, "Dry run, plugin %s is enabled for %d tenants and would be uninstalled by:", pluginID, len(tenants))
+			for _, req := range reqs {
+				fmt.Println(req)
+			}
+			return
+		}
 		if force {
 			tenantList, err := kubernetes.TenantPluginList(pluginID)
 			if err != nil {
@@ -47,5 +64,7 @@ tkeel plugin uninstall <plugin-id>
 
 func init() {
 	PluginUninstallCmd.Flags().BoolVarP(&force, "force", "f", false, "force uninstall plugin, even if a tenant has enabled it.")
+	PluginUninstallCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "Print the requests uninstalling the plugin would send, without sending them")
+	PluginUninstallCmd.ValidArgsFunction = completion.PluginArg
 	PluginCmd.AddCommand(PluginUninstallCmd)
 }
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	portForwardKeepAlive bool
+	portForwardLocalPort int
+	portForwardAddress   string
+	portForwardAllowAll  bool
+	portForwardAppPort   bool
+	portForwardPod       string
+	portForwardReconnect bool
+	portForwardWaitReady time.Duration
+	portForwardByService bool
+)
+
+var PortForwardCmd = &cobra.Command{
+	Use:   "portforward",
+	Short: "Forward a local port to the dapr sidecar of a tKeel plugin",
+	Example: `
+# Check the dapr sidecar of target app can be reached through a port-forward
+tkeel portforward target
+
+# Keep forwarding a local port to target app until Ctrl+C, then call it with curl
+tkeel portforward target --keep-alive --local-port 3500
+curl http://127.0.0.1:3500/v1.0/invoke/target/method/v1/sample
+
+# Keep forwarding but give up when the pod restarts instead of reconnecting
+tkeel portforward target --keep-alive --reconnect=false
+
+# Wait up to 2 minutes for target app to be ready after a deploy, then keep forwarding
+tkeel portforward target --keep-alive --wait-ready 2m
+
+# Keep forwarding a local port to port 80 of the nginx service in the default namespace
+tkeel portforward default/nginx:80 --by-service --keep-alive
+
+# Forward to the port of target app itself instead of its dapr sidecar
+tkeel portforward target --keep-alive --app-port
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel portforward <plugin-id> --keep-alive")
+			os.Exit(1)
+		}
+		pluginID := args[0]
+
+		if err := kubernetes.ValidateAddress(portForwardAddress, portForwardAllowAll); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+
+		if portForwardByService {
+			forwardService(pluginID)
+			return
+		}
+
+		remotePort := kubernetes.WithHTTPPort
+		if portForwardAppPort {
+			remotePort = kubernetes.WithAppPort
+		}
+		options := []kubernetes.PortForwardConfigureOption{
+			remotePort,
+			kubernetes.WithAppPod,
+			kubernetes.WithAddress(portForwardAddress),
+			kubernetes.WithLocalPort(portForwardLocalPort),
+			kubernetes.WithProgress,
+		}
+		if portForwardKeepAlive && portForwardReconnect {
+			options = append(options, kubernetes.WithAutoReconnect)
+		}
+		pod := portForwardPod
+		if portForwardWaitReady > 0 {
+			pod = waitPluginReady(pluginID, pod, portForwardWaitReady)
+		}
+		pf, err := kubernetes.GetPortforwardForPod(pluginID, pod, options...)
+		if err != nil {
+			failPlugin(pluginID, err)
+		}
+		runPortForward(pf, fmt.Sprintf("plugin %s", pluginID))
+	},
+}
+
+// runPortForward establishes the forward to target and reports its local
+// addresses, then keeps it open until interrupted with --keep-alive.
+func runPortForward(pf *kubernetes.PortForward, target string) {
+	if err := pf.Init(); err != nil {
+		pf.Stop()
+		print.FailureStatusEvent(os.Stdout, "Error forwarding to %s: %s", target, err)
+		os.Exit(1)
+	}
+
+	app := pf.AppPod()
+	for _, addr := range strings.Split(pf.Host, ",") {
+		host := net.JoinHostPort(strings.TrimSpace(addr), strconv.Itoa(pf.LocalPort))
+		print.InfoStatusEvent(os.Stdout, "Forwarding http://%s -> %s/%s:%d", host, app.Namespace, app.PodName, pf.RemotePort)
+	}
+	if !portForwardKeepAlive {
+		pf.Stop()
+		print.SuccessStatusEvent(os.Stdout, "Port-forward to %s works, pass --keep-alive to keep it open", target)
+		return
+	}
+
+	print.InfoStatusEvent(os.Stdout, "Press Ctrl+C to stop forwarding")
+	// the forward stops itself on interrupt, leaving the teardown to us.
+	<-pf.GetStop()
+	print.SuccessStatusEvent(os.Stdout, "Port-forward to %s stopped", target)
+}
+
+// forwardService forwards a local port to a pod behind the port of a
+// kubernetes service rather than to the dapr sidecar of a plugin.
+func forwardService(target string) {
+	if portForwardPod != "" || portForwardAppPort || portForwardWaitReady > 0 {
+		print.FailureStatusEvent(os.Stdout, "--pod, --app-port and --wait-ready can't be used with --by-service")
+		os.Exit(kubernetes.ExitUsage)
+	}
+	svc, err := kubernetes.ParseServiceTarget(target)
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(kubernetes.ExitUsage)
+	}
+	pf, err := kubernetes.GetPortforwardForService(svc,
+		kubernetes.WithAddress(portForwardAddress),
+		kubernetes.WithLocalPort(portForwardLocalPort),
+		kubernetes.WithProgress,
+	)
+	if err != nil {
+		failPlugin(target, err)
+	}
+	runPortForward(pf, fmt.Sprintf("service %s", svc))
+}
+
+// waitPluginReady waits for a ready pod of the plugin and returns its name,
+// exiting when none becomes ready in time.
+func waitPluginReady(pluginID, podName string, timeout time.Duration) string {
+	client, err := kubernetes.Client()
+	if err != nil {
+		failPlugin(pluginID, err)
+	}
+	app, err := kubernetes.WaitAppPodReady(context.Background(), client, pluginID, podName, timeout)
+	if err != nil {
+		failPlugin(pluginID, err)
+	}
+	return app.PodName
+}
+
+func init() {
+	PortForwardCmd.Flags().BoolVarP(&portForwardKeepAlive, "keep-alive", "k", false, "Keep the port-forward open until interrupted")
+	PortForwardCmd.Flags().IntVarP(&portForwardLocalPort, "local-port", "", 0, "The local port the port-forward listens on, 0 picks a random port")
+	PortForwardCmd.Flags().StringVarP(&portForwardAddress, "address", "", kubernetes.DefaultAddress, "Comma separated local addresses the port-forward listens on")
+	PortForwardCmd.Flags().BoolVarP(&portForwardAllowAll, "allow-all", "", false, "Allow the port-forward to listen on all interfaces (0.0.0.0)")
+	PortForwardCmd.Flags().BoolVarP(&portForwardAppPort, "app-port", "", false, "Forward to the port of the plugin itself instead of its dapr HTTP port")
+	PortForwardCmd.Flags().StringVarP(&portForwardPod, "pod", "", "", "The pod of the plugin to forward to, the first running pod is used if empty")
+	PortForwardCmd.Flags().BoolVarP(&portForwardReconnect, "reconnect", "", true, "With --keep-alive, reconnect to the running pod of the plugin when the port-forward drops")
+	PortForwardCmd.Flags().DurationVarP(&portForwardWaitReady, "wait-ready", "", 0, "How long to wait for a pod of the plugin to be running and ready, 0 fails right away when none is running")
+	PortForwardCmd.Flags().BoolVarP(&portForwardByService, "by-service", "", false, "Take the argument as a namespace/service:port kubernetes service and forward to one of its pods instead of a dapr sidecar")
+	PortForwardCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	PortForwardCmd.ValidArgsFunction = completion.PluginArg
+	RootCmd.AddCommand(PortForwardCmd)
+}
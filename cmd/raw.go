@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+	"github.com/tkeel-io/cli/pkg/utils"
+)
+
+var (
+	rawVerb    string
+	rawData    string
+	rawHeaders []string
+	rawPod     string
+)
+
+var RawCmd = &cobra.Command{
+	Use:   "raw",
+	Short: "Send a request to any path of the dapr HTTP API of a tKeel plugin",
+	Long: `Send a request to any path of the dapr HTTP API of a tKeel plugin, through a
+port-forward to its dapr sidecar. The path is relative to the dapr HTTP base,
+so it includes the API version, e.g. v1.0/metadata.
+
+Without --verb the request is sent as POST when a body is given with --data,
+and as GET otherwise.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Read the metadata of the dapr sidecar of target app
+tkeel raw target v1.0/metadata
+
+# Read a key from a state store of target app
+tkeel raw target v1.0/state/statestore/mykey
+
+# Publish an event through a pub/sub component of target app
+tkeel raw target v1.0/publish/pubsub/orders --data '{"id":"abc"}'
+
+# Save state with a custom header
+tkeel raw target v1.0/state/statestore --data '[{"key":"k","value":"v"}]' -H "X-Trace: abc"
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the plugin id and the path")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel raw <plugin-id> v1.0/metadata")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		pluginID, path := args[0], args[1]
+
+		header, err := utils.ParseHeaders(rawHeaders)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		verb := rawVerb
+		if verb == "" {
+			verb = defaultVerb(rawData != "")
+		}
+		invoker := &kubernetes.Invoker{
+			Header: header,
+			Pod:    rawPod,
+		}
+		res, err := invoker.RawByPortForward(pluginID, path, []byte(rawData), verb)
+		if err != nil {
+			failPlugin(pluginID, fmt.Errorf("error requesting %s of plugin %s: %w", path, pluginID, err))
+		}
+		if res.Body != "" {
+			fmt.Println(res.Body)
+		}
+	},
+}
+
+func init() {
+	RawCmd.Flags().StringVarP(&rawVerb, "verb", "v", "", "The HTTP verb to use, defaults to POST with a body and GET without")
+	RawCmd.Flags().StringVarP(&rawData, "data", "d", "", "The request body (optional)")
+	RawCmd.Flags().StringArrayVarP(&rawHeaders, "header", "H", []string{}, "A 'Key: Value' header to add to the request, can be repeated")
+	RawCmd.Flags().StringVarP(&rawPod, "pod", "", "", "The pod of the plugin to send the request to, the first running pod is used if empty")
+	RawCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RawCmd.ValidArgsFunction = completion.PluginArg
+	RootCmd.AddCommand(RawCmd)
+}
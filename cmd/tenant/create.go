@@ -1,23 +1,44 @@
 package tenant
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
+	"github.com/tkeel-io/cli/pkg/utils"
+)
+
+const generatedPasswordLength = 16
+
+var (
+	username         string
+	password         string
+	passwordStdin    bool
+	generatePassword bool
+	remark           string
 )
 
-var username string
-var password string
-var remark string
 var TenantCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create new tenant.",
 	Example: `
-# Create tenant
+# Create tenant, the missing values are prompted for
 tkeel tenant create [tenant-space-name]
+
+# Create tenant with an admin user
+tkeel tenant create <tenant-space-name> --admin-user <username> --admin-password <password>
+
+# Create tenant with the admin password read from stdin
+echo "<password>" | tkeel tenant create <tenant-space-name> --admin-user <username> --admin-password-stdin
+
+# Create tenant with a generated admin password, which is printed once
+tkeel tenant create <tenant-space-name> --admin-user <username> --generate-admin-password
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		var title string
@@ -30,6 +51,33 @@ tkeel tenant create [tenant-space-name]
 		} else {
 			title = args[0]
 		}
+		if passwordStdin && password != "" {
+			print.FailureStatusEvent(os.Stdout, "--admin-password and --admin-password-stdin are mutually exclusive")
+			os.Exit(1)
+		}
+		if generatePassword && (passwordStdin || password != "") {
+			print.FailureStatusEvent(os.Stdout, "--generate-admin-password can't be used with --admin-password or --admin-password-stdin")
+			os.Exit(1)
+		}
+		// passwordSource tells the admin where the password they log in with came from.
+		passwordSource := "given with --admin-password"
+		switch {
+		case generatePassword:
+			generated, err := utils.GeneratePassword(generatedPasswordLength)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			password = generated
+		case passwordStdin:
+			passwordSource = "read from stdin"
+			b, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			password = strings.TrimRight(string(b), "\r\n")
+		}
 		if username == "" {
 			err := survey.AskOne(&survey.Input{Message: "What the tenant admin username?"}, &username)
 			if err != nil {
@@ -38,26 +86,48 @@ tkeel tenant create [tenant-space-name]
 			}
 		}
 		if password == "" {
+			passwordSource = "entered at the prompt"
 			err := survey.AskOne(&survey.Password{Message: "What the tenant admin password?"}, &password)
 			if err != nil {
 				print.FailureStatusEvent(os.Stdout, err.Error())
 				os.Exit(1)
 			}
 		}
-		err := kubernetes.TenantCreate(title, remark, username, password)
+		tenantID, err := kubernetes.TenantCreate(title, remark, username, password)
 		if err != nil {
+			if errors.Is(err, kubernetes.ErrTenantExists) {
+				print.FailureStatusEvent(os.Stdout, "Tenant %s already exists", title)
+				os.Exit(1)
+			}
 			print.FailureStatusEvent(os.Stdout, err.Error())
 			os.Exit(1)
 		}
 
-		print.SuccessStatusEvent(os.Stdout, "Successfully created!")
+		print.SuccessStatusEvent(os.Stdout, "Successfully created tenant %s, its ID is:", title)
+		// the ID is the result of the command, --quiet keeps it.
+		fmt.Println(tenantID)
+		if username != "" && password != "" {
+			print.InfoStatusEvent(os.Stdout, "Tenant admin username: %s", username)
+			if generatePassword {
+				print.InfoStatusEvent(os.Stdout, "Tenant admin password, shown only once, please keep it safe:")
+				fmt.Println(password)
+			} else {
+				print.InfoStatusEvent(os.Stdout, "Tenant admin password: the one %s", passwordSource)
+			}
+		}
 	},
 }
 
 func init() {
 	TenantCreateCmd.Flags().BoolP("help", "h", false, "Print this help message")
-	TenantCreateCmd.Flags().StringVarP(&username, "username", "u", "", "username of tenant")
-	TenantCreateCmd.Flags().StringVarP(&password, "password", "p", "", "password of tenant")
+	TenantCreateCmd.Flags().StringVarP(&username, "admin-user", "u", "", "Username of the tenant admin")
+	TenantCreateCmd.Flags().StringVarP(&password, "admin-password", "p", "", "Password of the tenant admin")
+	TenantCreateCmd.Flags().BoolVarP(&passwordStdin, "admin-password-stdin", "", false, "Read the password of the tenant admin from stdin")
+	TenantCreateCmd.Flags().BoolVarP(&generatePassword, "generate-admin-password", "", false, "Generate the password of the tenant admin and print it once")
+	TenantCreateCmd.Flags().StringVar(&username, "username", "", "Username of the tenant admin")
+	TenantCreateCmd.Flags().StringVar(&password, "password", "", "Password of the tenant admin")
+	TenantCreateCmd.Flags().MarkDeprecated("username", "use --admin-user instead")
+	TenantCreateCmd.Flags().MarkDeprecated("password", "use --admin-password instead")
 	TenantCreateCmd.Flags().StringVarP(&remark, "remark", "r", "", "remark of tenant")
 	TenantCmd.AddCommand(TenantCreateCmd)
 }
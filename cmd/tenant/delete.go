@@ -1,19 +1,38 @@
 package tenant
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
+var (
+	yes    bool
+	force  bool
+	dryRun bool
+)
+
 var TenantDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete tenant info.",
 	Example: `
 # Delete tenant by tenant id
 tkeel tenant delete <tenant-id>
+
+# Delete tenant without confirmation
+tkeel tenant delete <tenant-id> --yes
+
+# Delete tenant together with its users
+tkeel tenant delete <tenant-id> --force
+
+# Show what deleting the tenant would remove and the request it would send
+tkeel tenant delete <tenant-id> --dry-run
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
@@ -21,17 +40,52 @@ tkeel tenant delete <tenant-id>
 			os.Exit(1)
 		}
 		tenantID := args[0]
-		err := kubernetes.TenantDelete(tenantID)
+		if dryRun {
+			summary, req, err := kubernetes.DryRunDeleteTenant(tenantID, force)
+			if err != nil {
+				failDelete(err)
+			}
+			print.InfoStatusEvent(os.Stdout, "Dry run, tenant %s would be deleted with %d users and %d plugin enablements by:", tenantID, summary.Users, summary.Plugins)
+			fmt.Print(req)
+			return
+		}
+		if !yes {
+			var confirm bool
+			err := survey.AskOne(&survey.Confirm{Message: "Do you want to delete tenant " + tenantID + " ?"}, &confirm)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Could not confirm the deletion: %s, use --yes to delete the tenant without confirmation", err.Error())
+				os.Exit(1)
+			}
+			if !confirm {
+				os.Exit(0)
+			}
+		}
+
+		summary, err := kubernetes.DeleteTenant(tenantID, force)
 		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			failDelete(err)
+		}
+		if force {
+			print.InfoStatusEvent(os.Stdout, "Deleted %d users and %d plugin enablements with the tenant", summary.Users, summary.Plugins)
 		}
 		print.SuccessStatusEvent(os.Stdout, "Successfully delete!")
-
 	},
 }
 
+func failDelete(err error) {
+	if errors.Is(err, kubernetes.ErrTenantHasUsers) {
+		print.FailureStatusEvent(os.Stdout, "%s, pass --force to delete them with the tenant", err)
+		os.Exit(1)
+	}
+	print.FailureStatusEvent(os.Stdout, err.Error())
+	os.Exit(1)
+}
+
 func init() {
 	TenantDeleteCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	TenantDeleteCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the tenant without confirmation")
+	TenantDeleteCmd.Flags().BoolVarP(&force, "force", "f", false, "Delete the tenant even if it still has users")
+	TenantDeleteCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "Print what would be deleted and the request that would be sent, without deleting")
+	TenantDeleteCmd.ValidArgsFunction = completion.TenantArg
 	TenantCmd.AddCommand(TenantDeleteCmd)
 }
@@ -3,14 +3,17 @@ package tenant
 import (
 	"os"
 
-	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
 	"github.com/tkeel-io/cli/fmtutil"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
-var pluginID string
+var (
+	pluginID string
+	search   string
+)
+
 var TenantListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all tenant.",
@@ -18,39 +21,44 @@ var TenantListCmd = &cobra.Command{
 # List tenant
 tkeel tenant list
 tkeel tenant list -p <pluginID>
+
+# List the tenants whose title contains the keyword
+tkeel tenant list --search <keyword>
+
+# List tenant as JSON
+tkeel tenant list -o json
+
+# List the tenant titles with their remarks, "-" when they have none
+tkeel tenant list -o go-template='{{.Title}} {{.Remark | default "-"}}'
 `,
 	Run: func(cmd *cobra.Command, args []string) {
+		format, err := fmtutil.ResolveFormat(outputFormat, outputTemplate)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+		outputFormat = format
 		if pluginID != "" {
 			data, err := kubernetes.TenantPluginList(pluginID)
 			if err != nil {
 				print.FailureStatusEvent(os.Stdout, err.Error())
 				os.Exit(1)
 			}
-			table, err := gocsv.MarshalString(data)
-			if err != nil {
-				print.FailureStatusEvent(os.Stdout, err.Error())
-				os.Exit(1)
-			}
-			fmtutil.PrintTable(table)
-			os.Exit(1)
+			outputList(data)
+			return
 		}
-		data, err := kubernetes.TenantList()
+		data, err := kubernetes.ListTenants(search)
 		if err != nil {
 			print.FailureStatusEvent(os.Stdout, err.Error())
 			os.Exit(1)
 		}
-		table, err := gocsv.MarshalString(data)
-		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
-		}
-
-		fmtutil.PrintTable(table)
+		outputList(data)
 	},
 }
 
 func init() {
 	TenantListCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	TenantListCmd.Flags().StringVarP(&pluginID, "plugin", "p", "", "List the tenant that enabled the plugin")
+	TenantListCmd.Flags().StringVarP(&search, "search", "s", "", "Only list the tenants whose title contains this keyword")
 	TenantCmd.AddCommand(TenantListCmd)
 }
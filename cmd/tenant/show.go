@@ -3,9 +3,8 @@ package tenant
 import (
 	"os"
 
-	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
-	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
@@ -29,16 +28,12 @@ tkeel tenant show <tenant-id>
 			print.FailureStatusEvent(os.Stdout, err.Error())
 			os.Exit(1)
 		}
-		table, err := gocsv.MarshalString(data)
-		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
-		}
-		fmtutil.PrintTable(table)
+		outputList(data)
 	},
 }
 
 func init() {
 	TenantInfoCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	TenantInfoCmd.ValidArgsFunction = completion.TenantArg
 	TenantCmd.AddCommand(TenantInfoCmd)
 }
@@ -1,7 +1,11 @@
 package tenant
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/print"
 )
 
 var TenantHelpExample = `
@@ -10,7 +14,14 @@ tkeel tenant create <tenant-name>
 tkeel tenant show <tenant-id>
 tkeel tenant delete <tenant-id>
 tkeel tenant list
+tkeel tenant list --search <title> -o json
 `
+
+var (
+	outputFormat   string
+	outputTemplate string
+)
+
 var TenantCmd = &cobra.Command{
 	Use:     "tenant",
 	Short:   "Tenant manage.",
@@ -21,5 +32,14 @@ var TenantCmd = &cobra.Command{
 }
 
 func init() {
+	TenantCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "The output format of the list. Valid values are: json, yaml, csv, go-template=<template>, or table (default)")
+	TenantCmd.PersistentFlags().StringVarP(&outputTemplate, "output-template", "", "", "Render each item of the list with this Go template, e.g. '{{.Name}}'. The helpers upper, lower, join, default and json are available")
 	TenantCmd.Flags().BoolP("help", "h", false, "Print this help message")
 }
+
+func outputList(list interface{}) {
+	if err := fmtutil.Render(os.Stdout, outputFormat, list); err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+}
@@ -20,23 +20,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/tkeel-io/cli/cmd/installer"
 	"github.com/tkeel-io/cli/cmd/upgrade"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"github.com/tkeel-io/cli/cmd/admin"
+	"github.com/tkeel-io/cli/cmd/auth"
+	"github.com/tkeel-io/cli/cmd/config"
 	"github.com/tkeel-io/cli/cmd/core"
 	"github.com/tkeel-io/cli/cmd/plugin"
 	"github.com/tkeel-io/cli/cmd/repo"
 	"github.com/tkeel-io/cli/cmd/tenant"
 	"github.com/tkeel-io/cli/cmd/user"
 	"github.com/tkeel-io/cli/pkg/api"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/print"
+	"github.com/tkeel-io/cli/pkg/utils"
 )
 
 var RootCmd = &cobra.Command{
@@ -52,15 +54,33 @@ var RootCmd = &cobra.Command{
 ===============================
 Things Keel Platform`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// checked before the config defaults mark --namespace as changed.
+		if allNS && cmd.Flags().Changed("namespace") && namespace != kubernetes.AllNamespaces {
+			print.FailureStatusEvent(os.Stdout, "--all-namespaces and --namespace are mutually exclusive")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if err := setup(cmd); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
 	},
 	Version: "0.4.0",
 }
 
 var (
-	logAsJSON  bool
-	kubeconfig string
-	verbose    bool
-	daprStatus *kubernetes.DaprStatus
+	logAsJSON    bool
+	noColor      bool
+	quiet        bool
+	defaultsFile string
+	profile      string
+	kubeconfig   string
+	namespace    string
+	allNS        bool
+	kubeCtx      string
+	asUser       string
+	asGroups     []string
+	verbose      int
+	daprStatus   *kubernetes.DaprStatus
 
 	gitCommit = ""
 	buildDate = ""
@@ -71,8 +91,6 @@ func Execute(version, apiVersion string) {
 	RootCmd.Version = version
 	api.PlatformAPIVersion = apiVersion
 
-	cobra.OnInitialize(initConfig, setKubConfig)
-
 	setVersion()
 
 	if err := RootCmd.Execute(); err != nil {
@@ -86,40 +104,79 @@ func setVersion() {
 	RootCmd.SetVersionTemplate(template)
 }
 
+// setKubConfig hands --kubeconfig to the kubernetes client, and to the
+// libraries building their own through $KUBECONFIG. The file is only checked
+// once a client is needed, so that commands not talking to the cluster, help
+// and completion keep working with a bad one.
 func setKubConfig() {
-	if kubeconfig != "" {
-		var err error
-		if kubeconfig, err = filepath.Abs(kubeconfig); err != nil {
-			print.WarningStatusEvent(os.Stdout, "get kubeconfig absolute path failed")
-			return
-		}
-		err = os.Setenv("KUBECONFIG", kubeconfig)
-		if err != nil {
-			print.WarningStatusEvent(os.Stdout, "set kubeconfig environment variable failed")
-			return
+	if kubeconfig == "" {
+		return
+	}
+	path := kubeconfig
+	if p, err := utils.GetRealPath(kubeconfig); err == nil {
+		if p, err = filepath.Abs(p); err == nil {
+			path = p
 		}
 	}
+	kubernetes.Kubeconfig = path
+	if err := os.Setenv("KUBECONFIG", path); err != nil {
+		print.WarningStatusEvent(os.Stdout, "set kubeconfig environment variable failed")
+	}
+}
+
+// setup applies the config defaults and the global flags before cmd runs,
+// or before its arguments are completed.
+func setup(cmd *cobra.Command) error {
+	if err := applyConfig(cmd); err != nil {
+		return err
+	}
+	if allNS {
+		namespace = kubernetes.AllNamespaces
+	}
+	initConfig()
+	setKubConfig()
+	return nil
 }
 
 func initConfig() {
 	if logAsJSON {
 		print.EnableJSONFormat()
 	}
-
-	viper.SetEnvPrefix("keel")
-	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-	viper.AutomaticEnv()
+	if noColor {
+		print.DisableColor()
+	}
+	if quiet {
+		print.EnableQuiet()
+	}
+	print.SetVerbosity(verbose)
+	kubernetes.Namespace = namespace
+	kubernetes.Context = kubeCtx
+	kubernetes.Impersonate = asUser
+	kubernetes.ImpersonateGroups = asGroups
 }
 
 func init() {
 	RootCmd.PersistentFlags().BoolVarP(&logAsJSON, "log-as-json", "", false, "log output in JSON format")
-	RootCmd.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "c", "", "the Kubernetes cluster which you want")
-	RootCmd.PersistentFlags().BoolVarP(&verbose, "verbose ", "V", false, "Show more output info")
+	RootCmd.PersistentFlags().BoolVarP(&noColor, "no-color", "", false, "Disable colored output, also set by the NO_COLOR environment variable or when stdout is not a terminal")
+	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print the result of the command, errors are printed on stderr")
+	RootCmd.PersistentFlags().StringVarP(&defaultsFile, "config", "", "", "Path to the config file holding defaults for the tenant, namespace, kubeconfig, context and output flags, defaults to ~/.tkeel/config.yaml")
+	RootCmd.PersistentFlags().StringVarP(&profile, "profile", "", "", "The profile of the config file to take defaults from, defaults to $KEEL_PROFILE then the current profile")
+	RootCmd.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "c", "", "Path to the kubeconfig file of the cluster, defaults to $KUBECONFIG then ~/.kube/config")
+	RootCmd.PersistentFlags().StringVarP(&kubeCtx, "context", "", "", "The kubeconfig context to use, defaults to the current context")
+	RootCmd.PersistentFlags().StringVarP(&asUser, "as", "", "", "The user to impersonate for the operations on the cluster, like kubectl --as")
+	RootCmd.PersistentFlags().StringArrayVarP(&asGroups, "as-group", "", []string{}, "A group to impersonate for the operations on the cluster, can be repeated, requires --as")
+	RootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "The namespace to look up plugin pods in, defaults to the namespace of the current kubeconfig context, 'all' looks in every namespace")
+	RootCmd.PersistentFlags().BoolVarP(&allNS, "all-namespaces", "A", false, "Look up plugin pods in every namespace, like -n all, failing when a plugin has pods in several namespaces")
+	RootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "V", "Show more output info, repeat to raise the level: 1 endpoints and pods, 2 headers, 3 port-forward logs")
+
+	completion.Setup = setup
 
 	RootCmd.AddCommand(plugin.PluginCmd)
 	RootCmd.AddCommand(tenant.TenantCmd)
 	RootCmd.AddCommand(core.CoreCmd)
 	RootCmd.AddCommand(admin.AdminCmd)
+	RootCmd.AddCommand(auth.AuthCmd)
+	RootCmd.AddCommand(config.ConfigCmd)
 	RootCmd.AddCommand(repo.RepoCmd)
 	RootCmd.AddCommand(user.UserCmd)
 	RootCmd.AddCommand(installer.InstallerCmd)
@@ -1,42 +1,83 @@
 package user
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
+var roles []string
+
 var UserCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create new user.",
+	Long:  "Create new user.\n\n" + kubernetes.ExitCodeHelp,
 	Example: `
-# Create user with username and password
-tkeel user create <username> <password> -t <tenant-id>
+# Create user, the password will be prompted for
+tkeel user create <username> -t <tenant-id>
+
+# Create user with password and roles
+tkeel user create <username> -t <tenant-id> --password <password> --role admin
+
+# Create user with the password read from stdin
+echo "<password>" | tkeel user create <username> -t <tenant-id> --password-stdin
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 2 {
-			print.WarningStatusEvent(os.Stdout, "Please specify the username and password")
-			print.WarningStatusEvent(os.Stdout, "For example, tkeel user create <username> <password> -t <tenant-id>")
-			os.Exit(1)
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the username")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel user create <username> -t <tenant-id>")
+			os.Exit(kubernetes.ExitUsage)
 		}
 		username := args[0]
-		password := args[1]
 
-		err := kubernetes.TenantUserCreate(tenant, username, password)
+		if passwordStdin && password != "" {
+			print.FailureStatusEvent(os.Stdout, "--password and --password-stdin are mutually exclusive")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		if passwordStdin {
+			var err error
+			password, err = readPasswordStdin()
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+		}
+		if password == "" {
+			err := survey.AskOne(&survey.Password{Message: "What the user password?"}, &password, survey.WithValidator(survey.Required))
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+		}
+
+		userID, err := kubernetes.CreateTenantUser(tenant, username, password, roles)
 		if err != nil {
+			if errors.Is(err, kubernetes.ErrUserExists) {
+				print.FailureStatusEvent(os.Stdout, "User %s already exists in tenant %s", username, tenant)
+				os.Exit(1)
+			}
 			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			os.Exit(kubernetes.ExitCode(err))
 		}
-		print.SuccessStatusEvent(os.Stdout, "Success! ")
+		print.SuccessStatusEvent(os.Stdout, "Created user %s, its ID is:", username)
+		// the ID is the result of the command, --quiet keeps it.
+		fmt.Println(userID)
 	},
 }
 
 func init() {
-	// UserCreateCmd.Flags().BoolVarP(&kubernetesMode, "kubernetes", "k", true, "List tenant's enabled plugins in a Kubernetes cluster")
 	UserCreateCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	UserCreateCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserCreateCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	UserCreateCmd.Flags().StringVarP(&password, "password", "p", "", "Password of the user")
+	UserCreateCmd.Flags().BoolVarP(&passwordStdin, "password-stdin", "", false, "Read the password from stdin")
+	UserCreateCmd.Flags().StringArrayVarP(&roles, "role", "r", []string{}, "Role to assign to the user, can be repeated")
 	UserCreateCmd.MarkFlagRequired("tenant")
 	UserCmd.AddCommand(UserCreateCmd)
 }
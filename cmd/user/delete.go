@@ -1,39 +1,87 @@
 package user
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
+var (
+	yes    bool
+	dryRun bool
+)
+
 var UserDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete user in tenant.",
+	Long:  "Delete user in tenant.\n\n" + kubernetes.ExitCodeHelp,
 	Example: `
 # Delete the user of tenant by user id
 tkeel user delete <user-id> -t <tenant-id>
+
+# Delete the user without confirmation
+tkeel user delete <user-id> -t <tenant-id> --yes
+
+# Show the request that would delete the user, without deleting it
+tkeel user delete <user-id> -t <tenant-id> --dry-run
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			print.WarningStatusEvent(os.Stdout, "Please specify the user id")
 			print.WarningStatusEvent(os.Stdout, "For example, tkeel user delete <user-id> -t <tenant-id>")
-			os.Exit(1)
+			os.Exit(kubernetes.ExitUsage)
 		}
 		userID := args[0]
-		err := kubernetes.TenantUserDelete(tenant, userID)
+		if dryRun {
+			req, err := kubernetes.DryRunDeleteTenantUser(tenant, userID)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(kubernetes.ExitCode(err))
+			}
+			print.InfoStatusEvent(os.Stdout, "Dry run, user %s of tenant %s would be deleted by:", userID, tenant)
+			fmt.Print(req)
+			return
+		}
+		if !yes {
+			var confirm bool
+			err := survey.AskOne(&survey.Confirm{Message: "Do you want to delete user " + userID + " of tenant " + tenant + " ?"}, &confirm)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Could not confirm the deletion: %s, use --yes to delete the user without confirmation", err.Error())
+				os.Exit(1)
+			}
+			if !confirm {
+				os.Exit(0)
+			}
+		}
+
+		err := kubernetes.DeleteTenantUser(tenant, userID)
 		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			switch {
+			case errors.Is(err, kubernetes.ErrUserNotFound):
+				print.FailureStatusEvent(os.Stdout, "User %s does not exist in tenant %s", userID, tenant)
+			case errors.Is(err, kubernetes.ErrPermissionDenied):
+				print.FailureStatusEvent(os.Stdout, "Not allowed to delete user %s, please login as admin again", userID)
+			default:
+				print.FailureStatusEvent(os.Stdout, err.Error())
+			}
+			os.Exit(kubernetes.ExitCode(err))
 		}
-		print.SuccessStatusEvent(os.Stdout, "Successfully delete!")
+		print.SuccessStatusEvent(os.Stdout, "Successfully deleted user %s", userID)
 	},
 }
 
 func init() {
 	UserDeleteCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	UserDeleteCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserDeleteCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	UserDeleteCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the user without confirmation")
+	UserDeleteCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "Print the request that would delete the user, without deleting it")
 	UserDeleteCmd.MarkFlagRequired("tenant")
 	UserCmd.AddCommand(UserDeleteCmd)
 }
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var (
+	importDryRun       bool
+	importSkipExisting bool
+	importRate         float64
+)
+
+var UserImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create users from a CSV file.",
+	Long: `Create users from a CSV file, one per row, reporting the result of each row.
+
+The file has a header line naming the columns USERNAME, PASSWORD, ROLES and
+TENANT ID, in any order. ROLES is a semicolon separated list and may be left
+out, as may TENANT ID, which must be the imported tenant when set. Other
+columns, such as the ID of an exported user list, are ignored.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Create the users of users.csv in the tenant
+tkeel user import users.csv -t <tenant-id>
+
+# Check users.csv without creating anyone
+tkeel user import users.csv -t <tenant-id> --dry-run
+
+# Create the users of users.csv, leaving the existing ones alone
+tkeel user import users.csv -t <tenant-id> --skip-existing
+
+# Create the users of users.csv, no more than 2 per second
+tkeel user import users.csv -t <tenant-id> --rate 2
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the CSV file")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel user import <file.csv> -t <tenant-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		limiter, err := kubernetes.NewRateLimiter(importRate)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, "Error reading users from '%s'. Error: %s", args[0], err)
+			os.Exit(1)
+		}
+		rows, err := kubernetes.ReadUserImport(f)
+		f.Close()
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		if len(rows) == 0 {
+			print.FailureStatusEvent(os.Stdout, "No user found in %s", args[0])
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		invalid := kubernetes.CheckUserImport(tenant, rows)
+		created, skipped, failed := 0, 0, 0
+		for _, row := range rows {
+			if err, ok := invalid[row.Line]; ok {
+				failed++
+				print.FailureStatusEvent(os.Stdout, "Line %d: %s", row.Line, err)
+				continue
+			}
+			if importDryRun {
+				continue
+			}
+			if err = limiter.Wait(context.Background()); err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(1)
+			}
+			userID, err := kubernetes.CreateTenantUser(tenant, row.Username, row.Password, row.RoleList())
+			switch {
+			case errors.Is(err, kubernetes.ErrUserExists) && importSkipExisting:
+				skipped++
+				print.WarningStatusEvent(os.Stdout, "Line %d: user %s already exists, skipped", row.Line, row.Username)
+			case err != nil:
+				failed++
+				print.FailureStatusEvent(os.Stdout, "Line %d: %s", row.Line, err)
+			default:
+				created++
+				print.SuccessStatusEvent(os.Stdout, "Line %d: created user %s with ID %s", row.Line, row.Username, userID)
+			}
+		}
+
+		if importDryRun {
+			if failed > 0 {
+				print.FailureStatusEvent(os.Stdout, "%d of %d users of %s are invalid", failed, len(rows), args[0])
+				os.Exit(kubernetes.ExitUsage)
+			}
+			print.SuccessStatusEvent(os.Stdout, "The %d users of %s are valid, none was created (dry run)", len(rows), args[0])
+			return
+		}
+		if failed > 0 {
+			print.FailureStatusEvent(os.Stdout, "Created %d users, skipped %d, %d failed", created, skipped, failed)
+			os.Exit(1)
+		}
+		print.SuccessStatusEvent(os.Stdout, "Created %d users, skipped %d", created, skipped)
+	},
+}
+
+func init() {
+	UserImportCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	UserImportCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserImportCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	UserImportCmd.Flags().BoolVarP(&importDryRun, "dry-run", "", false, "Check the file without creating any user")
+	UserImportCmd.Flags().BoolVarP(&importSkipExisting, "skip-existing", "", false, "Skip the users that already exist instead of failing on them")
+	UserImportCmd.Flags().Float64VarP(&importRate, "rate", "", 0, "The most users created per second, 0 means no limit")
+	UserImportCmd.MarkFlagRequired("tenant")
+	UserCmd.AddCommand(UserImportCmd)
+}
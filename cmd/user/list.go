@@ -3,39 +3,78 @@ package user
 import (
 	"os"
 
-	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
 	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
 
+var (
+	page     int
+	pageSize int
+	limit    int
+)
+
 var UserListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List user in tenant.",
+	Long:  "List user in tenant.\n\n" + kubernetes.ExitCodeHelp,
 	Example: `
 # List user info of tenant
 tkeel user list -t <tenant-id>
+
+# List at most 500 users of tenant
+tkeel user list -t <tenant-id> --limit 500
+
+# List only the second page of users of tenant, 20 users per page
+tkeel user list -t <tenant-id> --page 2 --page-size 20
+
+# List user info of tenant as YAML
+tkeel user list -t <tenant-id> -o yaml
+
+# Export the users of tenant to a spreadsheet
+tkeel user list -t <tenant-id> -o csv --output-file users.csv
+
+# List the IDs and names of the users of tenant, one per line
+tkeel user list -t <tenant-id> --output-template '{{.ID}} {{.Username}}'
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		data, err := kubernetes.TenantUserList(tenant)
+		format, err := fmtutil.ResolveFormat(outputFormat, outputTemplate)
 		if err != nil {
 			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			os.Exit(kubernetes.ExitUsage)
+		}
+		outputFormat = format
+		if page > 0 || pageSize > 0 {
+			data, err := kubernetes.TenantUsers(tenant, page, pageSize)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, err.Error())
+				os.Exit(kubernetes.ExitCode(err))
+			}
+			outputList(data)
+			return
 		}
-		table, err := gocsv.MarshalString(data)
+
+		data, capped, err := kubernetes.TenantAllUsers(tenant, limit)
 		if err != nil {
 			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			os.Exit(kubernetes.ExitCode(err))
 		}
-		fmtutil.PrintTable(table)
-
+		if capped {
+			print.WarningStatusEvent(os.Stderr, "Showing the first %d users of tenant %s, raise --limit to list more", limit, tenant)
+		}
+		outputList(data)
 	},
 }
 
 func init() {
 	UserListCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	UserListCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserListCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	UserListCmd.Flags().IntVarP(&page, "page", "", 0, "The page number to list, starting from 1")
+	UserListCmd.Flags().IntVarP(&pageSize, "page-size", "", 0, "The number of users per page")
+	UserListCmd.Flags().IntVarP(&limit, "limit", "", 0, "The maximum number of users to list, 0 lists all. Ignored with --page or --page-size")
 	UserListCmd.MarkFlagRequired("tenant")
 	UserCmd.AddCommand(UserListCmd)
 }
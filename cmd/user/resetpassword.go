@@ -0,0 +1,102 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+	"github.com/tkeel-io/cli/pkg/utils"
+)
+
+const generatedPasswordLength = 16
+
+var generatePassword bool
+
+var UserResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password",
+	Short: "Reset the password of user in tenant.",
+	Long:  "Reset the password of user in tenant.\n\n" + kubernetes.ExitCodeHelp,
+	Example: `
+# Reset the password of the user, the new password will be prompted for
+tkeel user reset-password <user-id> -t <tenant-id>
+
+# Reset the password with the new password read from stdin
+echo "<password>" | tkeel user reset-password <user-id> -t <tenant-id> --password-stdin
+
+# Reset the password to a generated one, which is printed once
+tkeel user reset-password <user-id> -t <tenant-id> --generate
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the user id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel user reset-password <user-id> -t <tenant-id>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		userID := args[0]
+		if passwordStdin && generatePassword {
+			print.FailureStatusEvent(os.Stdout, "--password-stdin and --generate are mutually exclusive")
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		newPassword, err := newUserPassword()
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+
+		err = kubernetes.ResetUserPassword(tenant, userID, newPassword)
+		if err != nil {
+			if errors.Is(err, kubernetes.ErrUserNotFound) {
+				print.FailureStatusEvent(os.Stdout, "User %s does not exist in tenant %s", userID, tenant)
+				os.Exit(kubernetes.ExitNotFound)
+			}
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		print.SuccessStatusEvent(os.Stdout, "Successfully reset the password of user %s", userID)
+		if generatePassword {
+			print.InfoStatusEvent(os.Stdout, "The new password is shown only once, please keep it safe")
+			fmt.Println(newPassword)
+		}
+	},
+}
+
+// newUserPassword returns the new password from stdin, a generator or
+// prompting twice, depending on the flags.
+func newUserPassword() (string, error) {
+	switch {
+	case passwordStdin:
+		return readPasswordStdin()
+	case generatePassword:
+		return utils.GeneratePassword(generatedPasswordLength)
+	}
+
+	var pw, confirm string
+	err := survey.AskOne(&survey.Password{Message: "New password:"}, &pw, survey.WithValidator(survey.Required))
+	if err != nil {
+		return "", err
+	}
+	err = survey.AskOne(&survey.Password{Message: "Confirm new password:"}, &confirm)
+	if err != nil {
+		return "", err
+	}
+	if pw != confirm {
+		return "", errors.New("passwords do not match")
+	}
+	return pw, nil
+}
+
+func init() {
+	UserResetPasswordCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	UserResetPasswordCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserResetPasswordCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	UserResetPasswordCmd.Flags().BoolVarP(&passwordStdin, "password-stdin", "", false, "Read the new password from stdin")
+	UserResetPasswordCmd.Flags().BoolVarP(&generatePassword, "generate", "g", false, "Generate a random strong password and print it once")
+	UserResetPasswordCmd.MarkFlagRequired("tenant")
+	UserCmd.AddCommand(UserResetPasswordCmd)
+}
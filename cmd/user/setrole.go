@@ -0,0 +1,53 @@
+package user
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var UserSetRoleCmd = &cobra.Command{
+	Use:   "set-role",
+	Short: "Set the roles of user in tenant.",
+	Long:  "Set the roles of user in tenant.\n\n" + kubernetes.ExitCodeHelp,
+	Example: `
+# Assign a role to the user of tenant
+tkeel user set-role <user-id> -t <tenant-id> --role admin
+
+# Assign several roles in one call
+tkeel user set-role <user-id> -t <tenant-id> --role admin --role viewer
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			print.WarningStatusEvent(os.Stdout, "Please specify the user id")
+			print.WarningStatusEvent(os.Stdout, "For example, tkeel user set-role <user-id> -t <tenant-id> --role <role>")
+			os.Exit(kubernetes.ExitUsage)
+		}
+		userID := args[0]
+
+		err := kubernetes.SetUserRole(tenant, userID, roles)
+		if err != nil {
+			if errors.Is(err, kubernetes.ErrUserNotFound) {
+				print.FailureStatusEvent(os.Stdout, "User %s does not exist in tenant %s", userID, tenant)
+				os.Exit(kubernetes.ExitNotFound)
+			}
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		print.SuccessStatusEvent(os.Stdout, "Successfully set roles of user %s", userID)
+	},
+}
+
+func init() {
+	UserSetRoleCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	UserSetRoleCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserSetRoleCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
+	UserSetRoleCmd.Flags().StringArrayVarP(&roles, "role", "r", []string{}, "Role to assign to the user, can be repeated")
+	UserSetRoleCmd.MarkFlagRequired("tenant")
+	UserSetRoleCmd.MarkFlagRequired("role")
+	UserCmd.AddCommand(UserSetRoleCmd)
+}
@@ -3,9 +3,8 @@ package user
 import (
 	"os"
 
-	"github.com/gocarina/gocsv"
 	"github.com/spf13/cobra"
-	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/completion"
 	"github.com/tkeel-io/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/print"
 )
@@ -13,35 +12,34 @@ import (
 var UserInfoCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show user info.",
+	Long:  "Show user info.\n\n" + kubernetes.ExitCodeHelp,
 	Example: `
 # Show user info by user id
 tkeel user show <user-id> -t <tenant-id>
 
+# Show user info as JSON
+tkeel user show <user-id> -t <tenant-id> -o json
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 2 {
+		if len(args) != 1 {
 			print.WarningStatusEvent(os.Stdout, "Please specify the user id")
 			print.WarningStatusEvent(os.Stdout, "For example, tkeel user show <user-id> -t <tenant-id>")
-			os.Exit(1)
+			os.Exit(kubernetes.ExitUsage)
 		}
 		userID := args[0]
 		data, err := kubernetes.TenantUserInfo(tenant, userID)
 		if err != nil {
 			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
+			os.Exit(kubernetes.ExitCode(err))
 		}
-		table, err := gocsv.MarshalString(data)
-		if err != nil {
-			print.FailureStatusEvent(os.Stdout, err.Error())
-			os.Exit(1)
-		}
-		fmtutil.PrintTable(table)
+		outputList(data)
 	},
 }
 
 func init() {
 	UserInfoCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	UserInfoCmd.Flags().StringVarP(&tenant, "tenant", "t", "", "Tenant ID")
+	UserInfoCmd.RegisterFlagCompletionFunc("tenant", completion.Tenants)
 	UserInfoCmd.MarkFlagRequired("tenant")
 	UserCmd.AddCommand(UserInfoCmd)
 }
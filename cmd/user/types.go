@@ -17,4 +17,11 @@ limitations under the License.
 package user
 
 // var kubernetesMode bool.
-var tenant string
+var (
+	tenant         string
+	outputFormat   string
+	outputTemplate string
+	outputFile     string
+	password       string
+	passwordStdin  bool
+)
@@ -1,20 +1,34 @@
 package user
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
 )
 
 const UserHelpExample = `
 # Manage plugins.
-tkeel user create <username> <password> -t <tenant-id>
+tkeel user create <username> -t <tenant-id> --password <password>
 tkeel user show <user-id> -t <tenant-id>
+tkeel user show <user-id> -t <tenant-id> -o json
 tkeel user delete <user-id> -t <tenant-id>
 tkeel user list -t <tenant-id>
+tkeel user list -t <tenant-id> -o csv --output-file users.csv
+tkeel user import users.csv -t <tenant-id>
+tkeel user set-role <user-id> -t <tenant-id> --role <role>
+tkeel user reset-password <user-id> -t <tenant-id>
 `
 
 var UserCmd = &cobra.Command{
 	Use:     "user",
 	Short:   "User manage of tenant.",
+	Long:    "User manage of tenant.\n\n" + kubernetes.ExitCodeHelp,
 	Example: UserHelpExample,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
@@ -22,5 +36,46 @@ var UserCmd = &cobra.Command{
 }
 
 func init() {
+	UserCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "The output format of the list. Valid values are: json, yaml, csv, go-template=<template>, or table (default)")
+	UserCmd.PersistentFlags().StringVarP(&outputTemplate, "output-template", "", "", "Render each item of the list with this Go template, e.g. '{{.Name}}'. The helpers upper, lower, join, default and json are available")
+	UserCmd.PersistentFlags().StringVarP(&outputFile, "output-file", "", "", "Write the output to this file instead of stdout")
 	UserCmd.Flags().BoolP("help", "h", false, "Print this help message")
 }
+
+func outputList(list interface{}) {
+	if outputFile == "" {
+		if err := fmtutil.Render(os.Stdout, outputFormat, list); err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, "error create output file: %s", err)
+		os.Exit(1)
+	}
+	err = fmtutil.Render(f, outputFormat, list)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		print.FailureStatusEvent(os.Stdout, err.Error())
+		os.Exit(1)
+	}
+	print.SuccessStatusEvent(os.Stdout, "Wrote %s", outputFile)
+}
+
+// readPasswordStdin reads a password piped to stdin, dropping the trailing newline.
+func readPasswordStdin() (string, error) {
+	b, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	pw := strings.TrimRight(string(b), "\r\n")
+	if pw == "" {
+		return "", errors.New("no password read from stdin")
+	}
+	return pw, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	daprapi "github.com/dapr/cli/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/tkeel-io/cli/pkg/api"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var versionClientOnly bool
+
+var VersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the CLI version and the tKeel version of the cluster",
+	Example: `
+# Print the CLI version and the tKeel control plane version of the cluster
+tkeel version
+
+# Print the CLI version only, without reaching the cluster
+tkeel version --client
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("CLI version: %s\n", RootCmd.Version)
+		fmt.Printf("Git commit: %s\n", gitCommit)
+		fmt.Printf("Build date: %s\n", buildDate)
+		fmt.Printf("Platform API version: %s\n", api.PlatformAPIVersion)
+		fmt.Printf("Dapr runtime API version: %s\n", daprapi.RuntimeAPIVersion)
+		if versionClientOnly {
+			return
+		}
+
+		status, err := kubernetes.CheckTKeel()
+		if err != nil {
+			print.WarningStatusEvent(os.Stdout, "Unable to get the tKeel version of the cluster: %s", err)
+			return
+		}
+		fmt.Printf("tKeel version: %s (namespace %s)\n", status.Version, status.Namespace)
+	},
+}
+
+func init() {
+	VersionCmd.Flags().BoolVarP(&versionClientOnly, "client", "", false, "Print the CLI version only, without querying the cluster")
+	VersionCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(VersionCmd)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/completion"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+	"github.com/tkeel-io/cli/pkg/utils"
+)
+
+var (
+	websocketAppID        string
+	websocketMethod       string
+	websocketData         string
+	websocketInteractive  bool
+	websocketPingInterval time.Duration
+	websocketPod          string
+	websocketBinary       bool
+	websocketOutputFile   string
+	websocketHandshake    time.Duration
+	websocketHeaders      []string
+	websocketSubprotocols []string
+	websocketRecord       string
+	websocketReplay       string
+)
+
+var WebsocketCmd = &cobra.Command{
+	Use:   "websocket",
+	Short: "Connect to a websocket method of a tKeel plugin",
+	Example: `
+# Send a message to the websocket method of target app and print the replies
+tkeel websocket --plugin-id target --method v1/ws --data '{"id":"abc"}'
+
+# Send every line typed on stdin as a message, Ctrl+D closes the connection
+tkeel websocket --plugin-id target --method v1/ws --interactive
+
+# Send a binary message and print the binary replies hex encoded
+tkeel websocket --plugin-id target --method v1/ws --data "$(printf '\x01\x02')" --binary
+
+# Write the payload of the binary replies to a file
+tkeel websocket --plugin-id target --method v1/ws --data 'dump' --output-file dump.bin
+
+# Connect to a secured websocket method of target app, negotiating a subprotocol
+tkeel websocket --plugin-id target --method v1/ws -H "Authorization: Bearer token" --subprotocol mqtt
+
+# Keep a long-lived connection alive by pinging the server every 30 seconds
+tkeel websocket --plugin-id target --method v1/ws --ping-interval 30s
+
+# Record the frames of an interactive session, then send the same messages again
+tkeel websocket --plugin-id target --method v1/ws --interactive --record session.ndjson
+tkeel websocket --plugin-id target --method v1/ws --replay session.ndjson
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		header, err := utils.ParseHeaders(websocketHeaders)
+		if err != nil {
+			print.FailureStatusEvent(os.Stdout, err.Error())
+			os.Exit(kubernetes.ExitUsage)
+		}
+
+		client := &kubernetes.WebsocketClient{
+			PingInterval:     websocketPingInterval,
+			Pod:              websocketPod,
+			Binary:           websocketBinary,
+			HandshakeTimeout: websocketHandshake,
+			Header:           header,
+			Subprotocols:     websocketSubprotocols,
+		}
+		if websocketInteractive {
+			client.Input = os.Stdin
+		}
+		if websocketReplay != "" {
+			if websocketInteractive || websocketData != "" {
+				print.FailureStatusEvent(os.Stdout, "--replay sends the recorded messages, it can't be used with --interactive or --data")
+				os.Exit(kubernetes.ExitUsage)
+			}
+			f, err := os.Open(websocketReplay)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error opening '%s'. Error: %s", websocketReplay, err)
+				os.Exit(1)
+			}
+			client.Replay, err = kubernetes.ReadWebsocketFrames(f)
+			f.Close()
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error reading '%s'. Error: %s", websocketReplay, err)
+				os.Exit(kubernetes.ExitCode(err))
+			}
+		}
+		if websocketRecord != "" {
+			f, err := os.OpenFile(websocketRecord, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error opening '%s'. Error: %s", websocketRecord, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			client.Record = f
+		}
+		if websocketOutputFile != "" {
+			f, err := os.Create(websocketOutputFile)
+			if err != nil {
+				print.FailureStatusEvent(os.Stdout, "Error creating '%s'. Error: %s", websocketOutputFile, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			client.BinaryOutput = f
+		}
+		resp, err := client.ByPortForward(websocketAppID, websocketMethod, []byte(websocketData))
+		if err != nil {
+			failPlugin(websocketAppID, fmt.Errorf("error connecting to plugin %s: %w", websocketAppID, err))
+		}
+		if resp != "" {
+			fmt.Println(resp)
+		}
+	},
+}
+
+func init() {
+	WebsocketCmd.Flags().StringVarP(&websocketAppID, "plugin-id", "p", "", "The application id to connect to")
+	WebsocketCmd.RegisterFlagCompletionFunc("plugin-id", completion.Plugins)
+	WebsocketCmd.Flags().StringVarP(&websocketMethod, "method", "m", "", "The websocket method to connect to")
+	WebsocketCmd.Flags().StringVarP(&websocketData, "data", "d", "", "The message sent once connected (optional)")
+	WebsocketCmd.Flags().BoolVarP(&websocketInteractive, "interactive", "i", false, "Send every line read from stdin as a message until EOF")
+	WebsocketCmd.Flags().DurationVarP(&websocketPingInterval, "ping-interval", "", 0, "Send a ping at this interval to keep the connection alive, 0 disables pings and waits for messages indefinitely")
+	WebsocketCmd.Flags().BoolVarP(&websocketBinary, "binary", "", false, "Send --data and the lines read with --interactive as binary messages instead of text ones")
+	WebsocketCmd.Flags().StringVarP(&websocketOutputFile, "output-file", "o", "", "Write the payload of the binary messages received to this file instead of printing them hex encoded")
+	WebsocketCmd.Flags().DurationVarP(&websocketHandshake, "handshake-timeout", "", kubernetes.DefaultHandshakeTimeout, "How long the websocket upgrade may take, 0 means no timeout")
+	WebsocketCmd.Flags().StringArrayVarP(&websocketHeaders, "header", "H", []string{}, "A 'Key: Value' header to send with the websocket upgrade, can be repeated")
+	WebsocketCmd.Flags().StringArrayVarP(&websocketSubprotocols, "subprotocol", "", []string{}, "A subprotocol to offer in Sec-WebSocket-Protocol, can be repeated in order of preference")
+	WebsocketCmd.Flags().StringVarP(&websocketRecord, "record", "", "", "Append every frame sent and received, with its time and type, to this NDJSON file")
+	WebsocketCmd.Flags().StringVarP(&websocketReplay, "replay", "", "", "Send the messages sent in a session recorded with --record, with the same delays, then close the connection")
+	WebsocketCmd.Flags().StringVarP(&websocketPod, "pod", "", "", "The pod of the plugin to connect to, the first running pod is used if empty")
+	WebsocketCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	WebsocketCmd.MarkFlagRequired("plugin-id")
+	WebsocketCmd.MarkFlagRequired("method")
+	RootCmd.AddCommand(WebsocketCmd)
+}
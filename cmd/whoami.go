@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/fmtutil"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+var whoamiOutput string
+
+var WhoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Print the user the cached token was issued to.",
+	Long: `Print the user the cached token was issued to: the user ID, tenant, roles
+and when the token expires. The claims are read from the token cached by
+tkeel auth login, its signature is only checked by the platform.
+
+` + kubernetes.ExitCodeHelp,
+	Example: `
+# Print the logged in user
+tkeel whoami
+
+# Print the logged in user as JSON
+tkeel whoami -o json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		info, err := kubernetes.CurrentTokenInfo()
+		if errors.Is(err, kubernetes.ErrNotLoggedIn) || errors.Is(err, kubernetes.ErrTokenExpired) {
+			print.FailureStatusEvent(os.Stderr, "Not logged in: %s", err)
+			os.Exit(kubernetes.ExitCode(err))
+		}
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, err.Error())
+			os.Exit(kubernetes.ExitCode(err))
+		}
+
+		if whoamiOutput != "" && whoamiOutput != fmtutil.FormatTable {
+			if err = fmtutil.Render(os.Stdout, whoamiOutput, info); err != nil {
+				print.FailureStatusEvent(os.Stderr, err.Error())
+				os.Exit(kubernetes.ExitUsage)
+			}
+			return
+		}
+		printTokenInfo(info)
+	},
+}
+
+func printTokenInfo(info *kubernetes.TokenInfo) {
+	orNone := func(s string) string {
+		if s == "" {
+			return "<none>"
+		}
+		return s
+	}
+	user := orNone(info.UserID)
+	if info.Username != "" && info.Username != info.UserID {
+		user = fmt.Sprintf("%s (%s)", user, info.Username)
+	}
+	expires := "never"
+	if !info.ExpiresAt.IsZero() {
+		expires = fmt.Sprintf("%s (in %s)", info.ExpiresAt.Local().Format(time.RFC3339), time.Until(info.ExpiresAt).Round(time.Second))
+	}
+	fmt.Printf("User:    %s\n", user)
+	fmt.Printf("Tenant:  %s\n", orNone(info.TenantID))
+	fmt.Printf("Roles:   %s\n", orNone(strings.Join(info.Roles, ", ")))
+	fmt.Printf("Expires: %s\n", expires)
+}
+
+func init() {
+	WhoamiCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	WhoamiCmd.Flags().StringVarP(&whoamiOutput, "output", "o", "", "The output format. Valid values are: json, yaml, or table (default)")
+	RootCmd.AddCommand(WhoamiCmd)
+}
@@ -8,8 +8,11 @@ import (
 )
 
 const (
+	_tkeelDir       = ".tkeel"
 	_tkeelRudderDir = ".tkeel/rudder"
 	_tokenFile      = ".token"
+	_authFile       = "token"
+	_defaultsFile   = "config.yaml"
 )
 
 func LocateAdminToken(flag int) (*os.File, error) {
@@ -20,6 +23,35 @@ func LocateAdminToken(flag int) (*os.File, error) {
 	return LocateFile(flag, homedir, _tkeelRudderDir, _tokenFile)
 }
 
+// AuthPath returns the path of the token cached by a login, ~/.tkeel/token,
+// kept apart from the ~/.tkeel/config.yaml defaults.
+func AuthPath() (string, error) {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "get user home dir failed")
+	}
+	return path.Join(homedir, _tkeelDir, _authFile), nil
+}
+
+// DefaultsPath returns the path of the file holding the flag defaults,
+// ~/.tkeel/config.yaml.
+func DefaultsPath() (string, error) {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "get user home dir failed")
+	}
+	return path.Join(homedir, _tkeelDir, _defaultsFile), nil
+}
+
+// AdminTokenPath returns the path of the cached admin token.
+func AdminTokenPath() (string, error) {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "get user home dir failed")
+	}
+	return path.Join(homedir, _tkeelRudderDir, _tokenFile), nil
+}
+
 func LocateFile(flag int, dir string, files ...string) (*os.File, error) {
 	filepath := dir
 	if len(files) != 0 {
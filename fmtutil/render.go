@@ -0,0 +1,110 @@
+package fmtutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/gocarina/gocsv"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats understood by Render.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatCSV   = "csv"
+)
+
+// Render writes v to w as a table, json, yaml or csv. An empty format renders
+// a table. Tables and csv are built from the csv tags of v, which may be a
+// struct or a slice; csv keeps the header row and quotes fields as needed.
+// go-template=... renders every item of v with the template, see
+// RenderTemplate.
+func Render(w io.Writer, format string, v interface{}) error {
+	if text, ok := templateText(format); ok {
+		tmpl, err := ParseTemplate(text)
+		if err != nil {
+			return err
+		}
+		return RenderTemplate(w, tmpl, v)
+	}
+
+	switch format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshal json: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case FormatYAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("error marshal yaml: %w", err)
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatCSV:
+		if err := gocsv.Marshal(asSlice(v), w); err != nil {
+			return fmt.Errorf("error marshal csv: %w", err)
+		}
+		return nil
+	case FormatTable, "":
+		table, err := gocsv.MarshalString(asSlice(v))
+		if err != nil {
+			return fmt.Errorf("error marshal table: %w", err)
+		}
+		WriteTable(w, table)
+		return nil
+	}
+	return invalidFormat(format)
+}
+
+// CheckFormat validates an output format before anything is rendered with
+// it, parsing the template of go-template=... formats.
+func CheckFormat(format string) error {
+	if text, ok := templateText(format); ok {
+		_, err := ParseTemplate(text)
+		return err
+	}
+	switch format {
+	case FormatJSON, FormatYAML, FormatCSV, FormatTable, "":
+		return nil
+	}
+	return invalidFormat(format)
+}
+
+// ResolveFormat folds a --output-template template into the output format
+// and validates the result, so that a bad template fails before anything is
+// rendered. A template can't be given together with a format.
+func ResolveFormat(format, template string) (string, error) {
+	if template != "" {
+		if format != "" {
+			return "", errors.New("--output-template can't be used with --output")
+		}
+		format = FormatTemplate + "=" + template
+	}
+	if err := CheckFormat(format); err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+func invalidFormat(format string) error {
+	return fmt.Errorf("invalid output format %q, valid values are: %s, %s, %s, %s, %s=<template>", format, FormatJSON, FormatYAML, FormatCSV, FormatTable, FormatTemplate)
+}
+
+// asSlice wraps a single value in a slice, as gocsv only marshals slices.
+func asSlice(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return v
+	}
+	s := reflect.MakeSlice(reflect.SliceOf(rv.Type()), 1, 1)
+	s.Index(0).Set(rv)
+	return s.Interface()
+}
@@ -0,0 +1,61 @@
+package fmtutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type renderItem struct {
+	ID   string `json:"id" csv:"id"`
+	Name string `json:"name" csv:"name"`
+}
+
+// tableFields collapses the padding of a rendered table, keeping its cells.
+func tableFields(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRender(t *testing.T) {
+	items := []renderItem{{ID: "1", Name: "alpha"}, {ID: "2", Name: "beta, gamma"}}
+	testCases := []struct {
+		name    string
+		format  string
+		v       interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "table", format: FormatTable, v: items[:1], want: "ID NAME\n1 alpha"},
+		{name: "default table", format: "", v: items[0], want: "ID NAME\n1 alpha"},
+		{name: "json", format: FormatJSON, v: items, want: "[\n  {\n    \"id\": \"1\",\n    \"name\": \"alpha\"\n  },\n  {\n    \"id\": \"2\",\n    \"name\": \"beta, gamma\"\n  }\n]\n"},
+		{name: "json single value", format: FormatJSON, v: items[0], want: "{\n  \"id\": \"1\",\n  \"name\": \"alpha\"\n}\n"},
+		{name: "yaml", format: FormatYAML, v: items, want: "- id: \"1\"\n  name: alpha\n- id: \"2\"\n  name: beta, gamma\n"},
+		{name: "csv", format: FormatCSV, v: items, want: "id,name\n1,alpha\n2,\"beta, gamma\"\n"},
+		{name: "csv single value", format: FormatCSV, v: items[0], want: "id,name\n1,alpha\n"},
+		{name: "invalid format", format: "xml", v: items, wantErr: `invalid output format "xml"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render(&buf, tc.format, tc.v)
+			if tc.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Empty(t, buf.String(), "expected nothing rendered")
+				return
+			}
+			assert.NoError(t, err)
+			got := buf.String()
+			if tc.format == FormatTable || tc.format == "" {
+				got = tableFields(got)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
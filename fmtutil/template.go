@@ -0,0 +1,77 @@
+package fmtutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// FormatTemplate renders every item with the Go template following it, as
+// in go-template={{.Name}}.
+const FormatTemplate = "go-template"
+
+// templateFuncs are the helpers available to output templates besides the
+// text/template builtins.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	// default returns value, or def when value is empty, so that
+	// {{.Email | default "-"}} fills the blanks.
+	"default": func(def, value interface{}) interface{} {
+		if value == nil {
+			return def
+		}
+		if v := reflect.ValueOf(value); v.IsZero() || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+			return def
+		}
+		return value
+	},
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses an output template, referring to unknown map keys is
+// an error when it is executed.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderTemplate executes tmpl for every item of v, a slice or a single
+// value, ending each one with a newline unless the template does. An item
+// failing to render stops before anything of it is written.
+func RenderTemplate(w io.Writer, tmpl *template.Template, v interface{}) error {
+	items := reflect.ValueOf(asSlice(v))
+	var buf bytes.Buffer
+	for i := 0; i < items.Len(); i++ {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, items.Index(i).Interface()); err != nil {
+			return fmt.Errorf("error render output template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateText returns the template of a go-template=... format.
+func templateText(format string) (string, bool) {
+	if !strings.HasPrefix(format, FormatTemplate+"=") {
+		return "", false
+	}
+	return strings.TrimPrefix(format, FormatTemplate+"="), true
+}
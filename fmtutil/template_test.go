@@ -0,0 +1,89 @@
+package fmtutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type templateItem struct {
+	Name   string
+	Remark string
+	Tags   []string
+}
+
+func TestRenderTemplate(t *testing.T) {
+	items := []templateItem{
+		{Name: "a", Remark: "first", Tags: []string{"x", "y"}},
+		{Name: "b"},
+	}
+	testCases := []struct {
+		name    string
+		format  string
+		v       interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "fields", format: "go-template={{.Name}}", v: items, want: "a\nb\n"},
+		{name: "upper", format: "go-template={{.Name | upper}}", v: items, want: "A\nB\n"},
+		{name: "default", format: `go-template={{.Name}} {{.Remark | default "-"}}`, v: items, want: "a first\nb -\n"},
+		{name: "join and default", format: `go-template={{join .Tags "," | default "none"}}`, v: items, want: "x,y\nnone\n"},
+		{name: "newline kept", format: "go-template={{.Name}}\n", v: items, want: "a\nb\n"},
+		{name: "single value", format: "go-template={{.Name}}", v: items[0], want: "a\n"},
+		{name: "empty list", format: "go-template={{.Name}}", v: []templateItem{}, want: ""},
+		{name: "parse error", format: "go-template={{.Name", v: items, wantErr: "invalid output template"},
+		{name: "unknown field", format: "go-template={{.Email}}", v: items, wantErr: "can't evaluate field Email"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render(&buf, tc.format, tc.v)
+			if tc.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Empty(t, buf.String(), "expected nothing rendered")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	for _, format := range []string{"", "table", "json", "yaml", "csv", "go-template={{.Name}}"} {
+		assert.NoError(t, CheckFormat(format), format)
+	}
+	assert.Error(t, CheckFormat("xml"))
+	assert.Error(t, CheckFormat("go-template={{if}}"))
+}
+
+func TestResolveFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{name: "format", format: "json", want: "json"},
+		{name: "default", want: ""},
+		{name: "template", template: "{{.Name}}", want: "go-template={{.Name}}"},
+		{name: "both", format: "yaml", template: "{{.Name}}", wantErr: "can't be used with --output"},
+		{name: "bad template", template: "{{if}}", wantErr: "invalid output template"},
+		{name: "bad format", format: "xml", wantErr: "invalid output format"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResolveFormat(tc.format, tc.template)
+			if tc.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package completion
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tkeel-io/cli/pkg/kubernetes"
+)
+
+// Setup applies the config defaults and the global flags, it is set by the
+// root command. Completion runs under cobra's hidden __complete command,
+// which skips the PersistentPreRun of the root command doing it.
+var Setup func(cmd *cobra.Command) error
+
+// setup runs Setup before talking to the cluster, reporting whether the
+// completion can go on.
+func setup(cmd *cobra.Command) bool {
+	return Setup == nil || Setup(cmd) == nil
+}
+
+// Tenants completes the value of a --tenant flag with the IDs of the tenants,
+// described by their title.
+func Tenants(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !setup(cmd) {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	list, err := kubernetes.ListTenants("")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ids := make([]string, 0, len(list))
+	for _, t := range list {
+		if strings.HasPrefix(t.ID, toComplete) {
+			ids = append(ids, t.ID+"\t"+t.Title)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// TenantArg completes the single tenant ID argument of a command.
+func TenantArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return Tenants(cmd, args, toComplete)
+}
+
+// Plugins completes the value of a --plugin-id flag with the IDs of the
+// plugins registered in the cluster.
+func Plugins(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !setup(cmd) {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	client, err := kubernetes.Client()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	list, err := kubernetes.ListPlugins(client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ids := make([]string, 0, len(list))
+	for _, p := range list {
+		if strings.HasPrefix(p.ID, toComplete) {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// PluginArg completes the single plugin ID argument of a command.
+func PluginArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return Plugins(cmd, args, toComplete)
+}
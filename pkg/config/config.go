@@ -0,0 +1,272 @@
+// Package config reads the defaults of the common flags from the config
+// file, ~/.tkeel/config.yaml unless --config points elsewhere.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/cli/fileutil"
+	"sigs.k8s.io/yaml"
+)
+
+// Keys of the config file, each one the default of the flag of the same name.
+const (
+	KeyTenant     = "tenant"
+	KeyNamespace  = "namespace"
+	KeyKubeconfig = "kubeconfig"
+	KeyContext    = "context"
+	KeyOutput     = "output"
+)
+
+// Keys lists the keys the config file may set.
+var Keys = []string{KeyTenant, KeyNamespace, KeyKubeconfig, KeyContext, KeyOutput}
+
+// ErrProfileNotFound is returned when a profile is not in the config file.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Defaults are the flag defaults set at the top of the config file or by a profile.
+type Defaults struct {
+	Tenant     string `json:"tenant,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Context    string `json:"context,omitempty"`
+	Output     string `json:"output,omitempty"`
+}
+
+// Get returns the value of key, empty when it is not set.
+func (d *Defaults) Get(key string) string {
+	switch key {
+	case KeyTenant:
+		return d.Tenant
+	case KeyNamespace:
+		return d.Namespace
+	case KeyKubeconfig:
+		return d.Kubeconfig
+	case KeyContext:
+		return d.Context
+	case KeyOutput:
+		return d.Output
+	}
+	return ""
+}
+
+// merge returns d with the keys set in over replaced.
+func (d Defaults) merge(over Defaults) Defaults {
+	if over.Tenant != "" {
+		d.Tenant = over.Tenant
+	}
+	if over.Namespace != "" {
+		d.Namespace = over.Namespace
+	}
+	if over.Kubeconfig != "" {
+		d.Kubeconfig = over.Kubeconfig
+	}
+	if over.Context != "" {
+		d.Context = over.Context
+	}
+	if over.Output != "" {
+		d.Output = over.Output
+	}
+	return d
+}
+
+// Config is the content of the config file. Profiles bundle the defaults
+// of one environment, e.g. a dev, staging or prod cluster, and the current
+// profile applies over the top level defaults.
+type Config struct {
+	Defaults
+	CurrentProfile string              `json:"current-profile,omitempty"`
+	Profiles       map[string]Defaults `json:"profiles,omitempty"`
+}
+
+// Resolve returns the defaults in effect with the named profile, or the
+// current profile when name is empty.
+func (c *Config) Resolve(name string) (*Defaults, error) {
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	d := c.Defaults
+	if name != "" {
+		p, ok := c.Profiles[name]
+		if !ok {
+			return nil, c.profileNotFound(name)
+		}
+		d = d.merge(p)
+	}
+	return &d, nil
+}
+
+// UseProfile makes the named profile the current one.
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return c.profileNotFound(name)
+	}
+	c.CurrentProfile = name
+	return nil
+}
+
+func (c *Config) profileNotFound(name string) error {
+	names := make([]string, 0, len(c.Profiles))
+	for n := range c.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return fmt.Errorf("%w: %s, no profiles are defined", ErrProfileNotFound, name)
+	}
+	return fmt.Errorf("%w: %s, available profiles are: %s", ErrProfileNotFound, name, strings.Join(names, ", "))
+}
+
+// Path returns file, or the default config file when file is empty.
+func Path(file string) (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	return fileutil.DefaultsPath()
+}
+
+// Load reads the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read config file failed")
+	}
+	c := &Config{}
+	if err = yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, errors.Wrapf(err, "invalid config file %s", path)
+	}
+	return c, nil
+}
+
+// LoadOrEmpty is Load returning an empty config when the file does not exist.
+func LoadOrEmpty(path string) (*Config, error) {
+	c, err := Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	return c, err
+}
+
+// Save writes c to the config file at path.
+func Save(path string, c *Config) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "marshal config failed")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "create config dir failed")
+	}
+	if err = ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrap(err, "write config file failed")
+	}
+	return nil
+}
+
+// ErrUnknownKey is returned when a key is not one of Keys.
+var ErrUnknownKey = errors.New("unknown key")
+
+// CheckKey returns an error suggesting the closest key when key is not one
+// of Keys.
+func CheckKey(key string) error {
+	for _, k := range Keys {
+		if k == key {
+			return nil
+		}
+	}
+	if s := suggestKey(key); s != "" {
+		return fmt.Errorf("%w: %s, did you mean %s?", ErrUnknownKey, key, s)
+	}
+	return fmt.Errorf("%w: %s, valid keys are: %s", ErrUnknownKey, key, strings.Join(Keys, ", "))
+}
+
+// Set sets key to value, an empty value unsets it.
+func (d *Defaults) Set(key, value string) error {
+	switch key {
+	case KeyTenant:
+		d.Tenant = value
+	case KeyNamespace:
+		d.Namespace = value
+	case KeyKubeconfig:
+		d.Kubeconfig = value
+	case KeyContext:
+		d.Context = value
+	case KeyOutput:
+		d.Output = value
+	default:
+		return CheckKey(key)
+	}
+	return nil
+}
+
+// Profile returns the defaults of the named profile, or the top level
+// defaults when name is empty.
+func (c *Config) Profile(name string) (*Defaults, error) {
+	if name == "" {
+		return &c.Defaults, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, c.profileNotFound(name)
+	}
+	return &p, nil
+}
+
+// SetProfile replaces the defaults of the named profile, or the top level
+// defaults when name is empty. The profile is created if needed.
+func (c *Config) SetProfile(name string, d Defaults) {
+	if name == "" {
+		c.Defaults = d
+		return
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Defaults{}
+	}
+	c.Profiles[name] = d
+}
+
+// suggestKey returns the key closest to key when it looks like a typo of it.
+func suggestKey(key string) string {
+	best, bestDist := "", 3
+	for _, k := range Keys {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min(n int, ns ...int) int {
+	for _, m := range ns {
+		if m < n {
+			n = m
+		}
+	}
+	return n
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// defaultAnnotation marks the flags set from the environment or the config
+// file rather than on the command line.
+const defaultAnnotation = "tkeel_config_default"
+
+// SetFlag sets the flag key of cmd to a default from the environment or the
+// config file, marking it as not given on the command line.
+func SetFlag(cmd *cobra.Command, key, value string) error {
+	if err := cmd.Flags().Set(key, value); err != nil {
+		return err
+	}
+	return cmd.Flags().SetAnnotation(key, defaultAnnotation, []string{"true"})
+}
+
+// Typed reports whether the flag key of cmd was given on the command line,
+// rather than left unset or set by SetFlag.
+func Typed(cmd *cobra.Command, key string) bool {
+	flag := cmd.Flags().Lookup(key)
+	return flag != nil && flag.Changed && flag.Annotations[defaultAnnotation] == nil
+}
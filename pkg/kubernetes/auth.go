@@ -0,0 +1,188 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/cli/fileutil"
+)
+
+var (
+	// ErrNotLoggedIn is returned when no token has been cached by a login.
+	ErrNotLoggedIn = errors.New("not logged in, please login with `tkeel auth login`")
+	// ErrTokenExpired is returned when the cached token is past its expiry.
+	ErrTokenExpired = errors.New("token expired, please re-login with `tkeel auth login`")
+)
+
+// authConfig is the login state cached in ~/.tkeel/token.
+type authConfig struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+}
+
+// Login logs the admin in and caches the token for later commands.
+func Login(password string) error {
+	resp, err := adminLogin(password)
+	if err != nil {
+		return err
+	}
+
+	conf := &authConfig{
+		AccessToken: resp.AccessToken,
+		TokenType:   resp.TokenType,
+	}
+	if resp.ExpiresIn > 0 {
+		conf.ExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return writeAuthConfig(conf)
+}
+
+// Logout clears the cached tokens, it is fine to logout when not logged in.
+func Logout() error {
+	for _, locate := range []func() (string, error){fileutil.AuthPath, fileutil.AdminTokenPath} {
+		path, err := locate()
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "remove cached token failed")
+		}
+	}
+	return nil
+}
+
+// CurrentToken returns the cached access token, ErrNotLoggedIn without a
+// login and ErrTokenExpired once the token expired.
+func CurrentToken() (string, error) {
+	path, err := fileutil.AuthPath()
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", ErrNotLoggedIn
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "read cached token failed")
+	}
+
+	conf := &authConfig{}
+	if err = json.Unmarshal(b, conf); err != nil {
+		return "", errors.Wrap(err, "parse cached token failed")
+	}
+	if conf.AccessToken == "" {
+		return "", ErrNotLoggedIn
+	}
+	if !conf.ExpiresAt.IsZero() && time.Now().After(conf.ExpiresAt) {
+		return "", ErrTokenExpired
+	}
+	return conf.AccessToken, nil
+}
+
+// TokenInfo is what the cached token tells about the logged in user.
+type TokenInfo struct {
+	UserID    string    `json:"user_id,omitempty"    yaml:"user_id,omitempty"`
+	Username  string    `json:"username,omitempty"   yaml:"username,omitempty"`
+	TenantID  string    `json:"tenant_id,omitempty"  yaml:"tenant_id,omitempty"`
+	Roles     []string  `json:"roles,omitempty"      yaml:"roles,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+}
+
+// CurrentTokenInfo decodes the claims of the cached token. The signature is
+// left to the platform, only the expiry is checked.
+func CurrentTokenInfo() (*TokenInfo, error) {
+	token, err := CurrentToken()
+	if err != nil {
+		return nil, err
+	}
+	info, err := parseTokenInfo(token)
+	if err != nil {
+		return nil, err
+	}
+	if !info.ExpiresAt.IsZero() && time.Now().After(info.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+	return info, nil
+}
+
+// parseTokenInfo reads the claims of a JWT token.
+func parseTokenInfo(token string) (*TokenInfo, error) {
+	parts := strings.Split(strings.TrimPrefix(token, "Bearer "), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("cached token is not a JWT, please re-login with `tkeel auth login`")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode token claims failed")
+	}
+	claims := map[string]interface{}{}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.Wrap(err, "parse token claims failed")
+	}
+
+	info := &TokenInfo{
+		UserID:   claimString(claims, "uid", "user_id", "sub"),
+		Username: claimString(claims, "username", "user", "name", "preferred_username"),
+		TenantID: claimString(claims, "tenant_id", "tid", "tenant"),
+		Roles:    claimStrings(claims, "roles", "role"),
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		info.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return info, nil
+}
+
+// claimString returns the first of keys set to a string in claims.
+func claimString(claims map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := claims[k].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// claimStrings returns the first of keys set in claims as a list, which may
+// be a JSON array or a comma or space separated string.
+func claimStrings(claims map[string]interface{}, keys ...string) []string {
+	for _, k := range keys {
+		switch v := claims[k].(type) {
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, e := range v {
+				if s, ok := e.(string); ok {
+					list = append(list, s)
+				}
+			}
+			return list
+		case string:
+			if v != "" {
+				return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+			}
+		}
+	}
+	return nil
+}
+
+func writeAuthConfig(conf *authConfig) error {
+	path, err := fileutil.AuthPath()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal token failed")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.Wrap(err, "create token dir failed")
+	}
+	// the token grants admin access, keep it private to the user.
+	return errors.Wrap(ioutil.WriteFile(path, b, 0600), "write cached token failed")
+}
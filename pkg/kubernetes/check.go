@@ -1,10 +1,28 @@
 package kubernetes
 
 import (
-	dapr "github.com/dapr/cli/pkg/kubernetes"
+	"context"
+	"fmt"
+	"strings"
+
 	helm "helm.sh/helm/v3/pkg/action"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// daprControlPlaneSelector selects the pods of the dapr control plane.
+	daprControlPlaneSelector = "app in (dapr-operator, dapr-sentry, dapr-placement-server, dapr-sidecar-injector)"
+	// daprSentryApp is the control plane app whose image tag is the dapr version.
+	daprSentryApp = "dapr-sentry"
+	// daprSystemConfig is the dapr configuration holding the mTLS settings.
+	daprSystemConfig = "daprsystem"
 )
 
+var daprConfigurations = schema.GroupVersionResource{Group: "dapr.io", Version: "v1alpha1", Resource: "configurations"}
+
 type DaprStatus struct {
 	Installed   bool   `json:"installed"`
 	Version     string `json:"version"`
@@ -26,33 +44,57 @@ func CheckDapr() (*DaprStatus, error) {
 		MTLSEnabled: false,
 	}
 
-	statusClient, err := dapr.NewStatusClient()
+	// go through the selected kubeconfig, context and impersonation like
+	// every other call to the cluster.
+	config, client, err := GetKubeConfigClient()
 	if err != nil {
 		return nil, err
 	}
-	status, err := statusClient.Status()
+	ctx := context.Background()
+	pods, err := client.CoreV1().Pods(v1.NamespaceAll).List(ctx, v1.ListOptions{LabelSelector: daprControlPlaneSelector})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error list dapr control plane pods: %w", err)
 	}
-	if len(status) == 0 {
+	if len(pods.Items) == 0 {
 		return nil, ErrDaprNotInstall
 	}
 	result.Installed = true
-	result.Namespace = status[0].Namespace
-	for _, item := range status {
-		if item.Name == "dapr-sentry" {
-			result.Version = item.Version
+	result.Namespace = pods.Items[0].Namespace
+	for _, pod := range pods.Items {
+		if pod.Labels["app"] == daprSentryApp && len(pod.Spec.Containers) > 0 {
+			image := pod.Spec.Containers[0].Image
+			result.Version = image[strings.LastIndex(image, ":")+1:]
 			break
 		}
 	}
-	enabled, err := dapr.IsMTLSEnabled()
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, &kubeConfigError{err}
+	}
+	result.MTLSEnabled, err = daprMTLSEnabled(ctx, dynamicClient)
 	if err != nil {
 		return nil, err
 	}
-	result.MTLSEnabled = enabled
 	return result, nil
 }
 
+// daprMTLSEnabled reports whether mTLS is enabled in the dapr system
+// configuration.
+func daprMTLSEnabled(ctx context.Context, client dynamic.Interface) (bool, error) {
+	list, err := client.Resource(daprConfigurations).Namespace(v1.NamespaceAll).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error list dapr configurations: %w", err)
+	}
+	for _, item := range list.Items {
+		if item.GetName() == daprSystemConfig {
+			enabled, _, err := unstructured.NestedBool(item.Object, "spec", "mtls", "enabled")
+			return enabled, err
+		}
+	}
+	return false, fmt.Errorf("dapr system configuration %s not found", daprSystemConfig)
+}
+
 func CheckTKeel() (*TKeelStatus, error) {
 	result := &TKeelStatus{
 		Installed: false,
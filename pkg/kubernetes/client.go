@@ -17,7 +17,186 @@ limitations under the License.
 package kubernetes
 
 import (
-	dapr "github.com/dapr/cli/pkg/kubernetes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Context overrides the current context of the kubeconfig when set.
+var Context string
+
+// Namespace is the namespace plugin pods are looked up in. When empty the
+// namespace of the current kubeconfig context is tried first, then all
+// namespaces. AllNamespaces looks them up in every namespace, refusing apps
+// with pods in several of them.
+var Namespace string
+
+// AllNamespaces is the Namespace looking up plugin pods in every namespace,
+// like kubectl -A.
+const AllNamespaces = "all"
+
+// Impersonate is the user the requests to the cluster are made as, and
+// ImpersonateGroups the groups, like kubectl --as and --as-group.
+var (
+	Impersonate       string
+	ImpersonateGroups []string
 )
 
-var Client = dapr.Client
+// Kubeconfig is the kubeconfig file given with --kubeconfig, $KUBECONFIG
+// then ~/.kube/config are used when empty.
+var Kubeconfig string
+
+// ErrKubeConfig is returned when the kubeconfig cannot be loaded or used.
+var ErrKubeConfig = errors.New("kubeconfig error")
+
+// kubeConfigError is an ErrKubeConfig keeping the error behind it in the
+// chain, so that both can be matched with errors.Is and errors.As.
+type kubeConfigError struct {
+	err error
+}
+
+func (e *kubeConfigError) Error() string {
+	return ErrKubeConfig.Error() + ": " + e.err.Error()
+}
+
+func (e *kubeConfigError) Is(target error) bool {
+	return target == ErrKubeConfig
+}
+
+func (e *kubeConfigError) Unwrap() error {
+	return e.err
+}
+
+// Client returns a clientset for the cluster of the selected kubeconfig context.
+func Client() (*k8s.Clientset, error) {
+	_, client, err := GetKubeConfigClient()
+	return client, err
+}
+
+// GetKubeConfigClient loads the kubeconfig from $KUBECONFIG or ~/.kube/config
+// and returns the rest config and clientset of the selected context.
+func GetKubeConfigClient() (*rest.Config, *k8s.Clientset, error) {
+	if err := checkKubeconfig(); err != nil {
+		return nil, nil, err
+	}
+	loader := clientConfig()
+	if Context != "" {
+		raw, err := loader.RawConfig()
+		if err != nil {
+			return nil, nil, &kubeConfigError{err}
+		}
+		if _, ok := raw.Contexts[Context]; !ok {
+			names := make([]string, 0, len(raw.Contexts))
+			for name := range raw.Contexts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, nil, fmt.Errorf("%w: context %q not found, available contexts are: %s", ErrKubeConfig, Context, strings.Join(names, ", "))
+		}
+	}
+
+	config, err := restConfig(loader)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := k8s.NewForConfig(config)
+	if err != nil {
+		return nil, nil, &kubeConfigError{err}
+	}
+	return config, client, nil
+}
+
+// restConfig returns the rest config of the context of loader, as the
+// impersonated user if any.
+func restConfig(loader clientcmd.ClientConfig) (*rest.Config, error) {
+	if len(ImpersonateGroups) > 0 && Impersonate == "" {
+		return nil, &UsageError{errors.New("impersonating a group requires impersonating a user too")}
+	}
+
+	config, err := loader.ClientConfig()
+	if err != nil {
+		return nil, &kubeConfigError{err}
+	}
+	if Impersonate != "" {
+		config.Impersonate = rest.ImpersonationConfig{UserName: Impersonate, Groups: ImpersonateGroups}
+	}
+	return config, nil
+}
+
+// checkKubeconfig makes sure the kubeconfig files given explicitly, with
+// Kubeconfig or $KUBECONFIG, are readable. A missing ~/.kube/config is left
+// for the kubernetes client to report.
+func checkKubeconfig() error {
+	if Kubeconfig != "" {
+		return checkKubeconfigFile(Kubeconfig, "--kubeconfig")
+	}
+	for _, path := range filepath.SplitList(os.Getenv(clientcmd.RecommendedConfigPathEnvVar)) {
+		if err := checkKubeconfigFile(path, clientcmd.RecommendedConfigPathEnvVar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkKubeconfigFile(path, source string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: kubeconfig %s given by %s does not exist", ErrKubeConfig, path, source)
+	}
+	if err != nil {
+		return &kubeConfigError{fmt.Errorf("kubeconfig %s given by %s is not readable: %w", path, source, err)}
+	}
+	return f.Close()
+}
+
+func clientConfig() clientcmd.ClientConfig {
+	return contextClientConfig(Context)
+}
+
+// contextClientConfig loads the kubeconfig with context as the current one,
+// the current context of the kubeconfig is kept if empty.
+func contextClientConfig(context string) clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// lookupNamespace returns the namespace to look up pods in and whether it was
+// guessed from the kubeconfig context rather than set explicitly.
+func lookupNamespace() (namespace string, guessed bool) {
+	if Namespace == AllNamespaces {
+		return v1.NamespaceAll, false
+	}
+	if Namespace != "" {
+		return Namespace, false
+	}
+	if ns := contextNamespace(); ns != "" {
+		return ns, true
+	}
+	return v1.NamespaceAll, false
+}
+
+// contextNamespace returns the namespace set on the selected kubeconfig context, if any.
+func contextNamespace() string {
+	config, err := clientConfig().RawConfig()
+	if err != nil {
+		return ""
+	}
+	name := config.CurrentContext
+	if Context != "" {
+		name = Context
+	}
+	if ctx, ok := config.Contexts[name]; ok {
+		return ctx.Namespace
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/tkeel-io/tkeel-interface/openapi/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ContextConcurrency bounds how many clusters are queried at once.
+	ContextConcurrency = 4
+	// contextQueryTimeout bounds each request to the cluster of a context,
+	// so that an unreachable cluster doesn't hold up the others.
+	contextQueryTimeout = 30 * time.Second
+)
+
+// ContextPluginListOutput is a plugin registered in the cluster of a context.
+type ContextPluginListOutput struct {
+	Context       string `csv:"CONTEXT"`
+	Name          string `csv:"NAME"`
+	PluginVersion string `csv:"PLUGIN VERSION"`
+	TkeelVersion  string `csv:"TKEEL VERSION"`
+	RegisterAt    string `csv:"REGISTER_AT"`
+	Status        string `csv:"STATE"`
+}
+
+// ContextError is the failure of a query to the cluster of a context.
+type ContextError struct {
+	Context string
+	Err     error
+}
+
+func (e *ContextError) Error() string {
+	return fmt.Sprintf("context %s: %s", e.Context, e.Err)
+}
+
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
+
+// KubeContexts returns the names of the contexts of the kubeconfig, sorted.
+func KubeContexts() ([]string, error) {
+	if err := checkKubeconfig(); err != nil {
+		return nil, err
+	}
+	raw, err := contextClientConfig("").RawConfig()
+	if err != nil {
+		return nil, &kubeConfigError{err}
+	}
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// contextClient returns a clientset for the cluster of the named context.
+func contextClient(name string) (k8s.Interface, error) {
+	config, err := restConfig(contextClientConfig(name))
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = contextQueryTimeout
+	client, err := k8s.NewForConfig(config)
+	if err != nil {
+		return nil, &kubeConfigError{err}
+	}
+	return client, nil
+}
+
+// ListPluginsOfContexts lists the plugins registered in the cluster of every
+// context, querying up to concurrency clusters at once. The plugins are
+// returned in the order of contexts, and the contexts that failed are
+// reported apart without stopping the others.
+func ListPluginsOfContexts(contexts []string, concurrency int) ([]ContextPluginListOutput, []*ContextError) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	lists := make([][]ContextPluginListOutput, len(contexts))
+	errs := make([]*ContextError, len(contexts))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				list, err := listContextPlugins(contexts[i])
+				if err != nil {
+					errs[i] = &ContextError{Context: contexts[i], Err: err}
+					continue
+				}
+				lists[i] = list
+			}
+		}()
+	}
+	for i := range contexts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var (
+		all      []ContextPluginListOutput
+		failures []*ContextError
+	)
+	for i := range contexts {
+		if errs[i] != nil {
+			failures = append(failures, errs[i])
+			continue
+		}
+		all = append(all, lists[i]...)
+	}
+	return all, failures
+}
+
+// listContextPlugins lists the plugins registered in the cluster of a context.
+func listContextPlugins(name string) ([]ContextPluginListOutput, error) {
+	client, err := contextClient(name)
+	if err != nil {
+		return nil, err
+	}
+	plugins, err := ListPlugins(client)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]ContextPluginListOutput, 0, len(plugins))
+	for _, p := range plugins {
+		list = append(list, ContextPluginListOutput{
+			Context:       name,
+			Name:          p.ID,
+			PluginVersion: p.PluginVersion,
+			TkeelVersion:  p.TkeelVersion,
+			RegisterAt:    time.Unix(p.RegisterTimestamp, 0).Format("2006-01-02 15:04:05"),
+			Status:        v1.PluginStatus_name[int32(p.Status)],
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
@@ -0,0 +1,73 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/dapr/cli/pkg/age"
+)
+
+// PluginDescribeOutput is the resolved dapr app of a pod of a plugin, what
+// invoke and port-forward target.
+type PluginDescribeOutput struct {
+	ID        string `csv:"ID"         json:"id"          yaml:"id"`
+	PodName   string `csv:"POD NAME"   json:"pod_name"    yaml:"pod_name"`
+	Namespace string `csv:"NAMESPACE"  json:"namespace"   yaml:"namespace"`
+	PodIP     string `csv:"POD IP"     json:"pod_ip"      yaml:"pod_ip"`
+	Phase     string `csv:"PHASE"      json:"phase"       yaml:"phase"`
+	Ready     string `csv:"READY"      json:"ready"       yaml:"ready"`
+	HTTPPort  int    `csv:"HTTP PORT"  json:"http_port"   yaml:"http_port"`
+	GRPCPort  int    `csv:"GRPC PORT"  json:"grpc_port"   yaml:"grpc_port"`
+	AppPort   int    `csv:"APP PORT"   json:"app_port"    yaml:"app_port"`
+	Version   string `csv:"VERSION"    json:"version"     yaml:"version"`
+	Age       string `csv:"AGE"        json:"age"         yaml:"age"`
+	Created   string `csv:"CREATED"    json:"created"     yaml:"created"`
+	InvokeURL string `csv:"INVOKE URL" json:"invoke_url"  yaml:"invoke_url"`
+	// PortForward is the kubectl command forwarding the dapr HTTP port
+	// InvokeURL is reachable on.
+	PortForward string `csv:"PORT FORWARD" json:"port_forward" yaml:"port_forward"`
+}
+
+// DescribePlugin resolves the dapr app of every pod of the plugin, running
+// ones first, or of the pod named podName only.
+func DescribePlugin(pluginID, podName string) ([]PluginDescribeOutput, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+	apps, err := GetAppPods(client, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]PluginDescribeOutput, 0, len(apps))
+	for _, app := range apps {
+		if podName != "" && app.PodName != podName {
+			continue
+		}
+		list = append(list, describeAppPod(app))
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%w: %s is not a pod of %s", ErrPodNotFound, podName, pluginID)
+	}
+	return list, nil
+}
+
+func describeAppPod(app *AppPod) PluginDescribeOutput {
+	status := podStatusOutput(app)
+	return PluginDescribeOutput{
+		ID:          app.AppID,
+		PodName:     app.PodName,
+		Namespace:   app.Namespace,
+		PodIP:       app.pod.Status.PodIP,
+		Phase:       status.Phase,
+		Ready:       status.Ready,
+		HTTPPort:    app.HTTPPort,
+		GRPCPort:    app.GRPCPort,
+		AppPort:     app.AppPort,
+		Version:     app.pod.Labels[versionLabel],
+		Age:         age.GetAge(app.pod.CreationTimestamp.Time),
+		Created:     app.pod.CreationTimestamp.Format("2006-01-02 15:04:05"),
+		InvokeURL:   app.InvokeURL(app.HTTPPort, "", URLOptions{}),
+		PortForward: fmt.Sprintf("kubectl port-forward -n %s pod/%s %d", app.Namespace, app.PodName, app.HTTPPort),
+	}
+}
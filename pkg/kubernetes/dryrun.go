@@ -0,0 +1,184 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/dapr/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// redacted replaces credentials in the requests printed by a dry run.
+const redacted = "<redacted>"
+
+// redactHeader returns the value of the header to print, redacted when it
+// carries credentials.
+func redactHeader(key, value string) string {
+	switch http.CanonicalHeaderKey(key) {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return redacted
+	}
+	return value
+}
+
+// DryRun composes the request InvokeByPortForward would send and returns it
+// as text, without forwarding a port or sending anything.
+func (inv *Invoker) DryRun(pluginID, method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+	verb, err := normalizeVerb(verb)
+	if err != nil {
+		return "", err
+	}
+	if err = inv.checkPatch(verb, data); err != nil {
+		return "", err
+	}
+	if _, err = inv.tlsConfig(); err != nil {
+		return "", err
+	}
+
+	reqOpts = append(inv.httpRequestOptions(), reqOpts...)
+	if inv.Service != nil {
+		u := "http://127.0.0.1/" + strings.TrimPrefix(escapeMethod(method), "/")
+		return describeURLRequest(u, data, verb, reqOpts...)
+	}
+	return describeRequest(pluginID, method, data, verb, reqOpts...)
+}
+
+// DryRunDeleteTenant resolves what deleting the tenant would remove and
+// returns the request that would delete it, without sending it.
+func DryRunDeleteTenant(tenantID string, force bool) (*TenantDeleteSummary, string, error) {
+	summary, err := checkDeleteTenant(tenantID, force)
+	if err != nil {
+		return nil, "", err
+	}
+	req, err := describeRequest(_pluginKeel, fmt.Sprintf(_deleteTenantMethodFormat, tenantID), nil, http.MethodDelete, setAuthenticate(redacted))
+	return summary, req, err
+}
+
+// DryRunDeleteTenantUser checks the user exists and returns the request that
+// would delete it, without sending it.
+func DryRunDeleteTenantUser(tenantID, userID string) (string, error) {
+	if _, err := TenantUserInfo(tenantID, userID); err != nil {
+		return "", errors.Wrapf(err, "error get user %s", userID)
+	}
+	return describeRequest(_pluginKeel, fmt.Sprintf(_deleteTenantUserMethodFormat, tenantID, userID), nil, http.MethodDelete, setAuthenticate(redacted))
+}
+
+// DryRunUninstallPlugin returns the tenants the plugin is enabled for and the
+// requests that would disable it for them, when force is set, and uninstall
+// it, without sending them.
+func DryRunUninstallPlugin(pluginID string, force bool) ([]TenantListOutPut, []string, error) {
+	tenants, err := TenantPluginList(pluginID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error list tenants of plugin")
+	}
+
+	var reqs []string
+	if force {
+		for _, t := range tenants {
+			req, err := describeRequest(_pluginKeel, fmt.Sprintf(_disablePluginFormat, pluginID, t.ID), nil, http.MethodDelete, setAuthenticate(redacted))
+			if err != nil {
+				return nil, nil, err
+			}
+			reqs = append(reqs, req)
+		}
+	}
+	req, err := describeRequest(_pluginKeel, fmt.Sprintf(_uninstallPluginFormat, pluginID), nil, http.MethodDelete, setAuthenticate(redacted))
+	if err != nil {
+		return nil, nil, err
+	}
+	return tenants, append(reqs, req), nil
+}
+
+// describeRequest renders the request an invoke of the plugin method would
+// send to the dapr sidecar, credentials redacted.
+func describeRequest(pluginID, method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+	u := fmt.Sprintf("http://127.0.0.1/v%s/invoke/%s/method/%s", api.RuntimeAPIVersion, pluginID, method)
+	return describeURLRequest(u, data, verb, reqOpts...)
+}
+
+// describeURLRequest renders the request to u, credentials redacted.
+func describeURLRequest(u string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+	req, err := http.NewRequest(verb, u, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("error creat http request: %w", err)
+	}
+	for i := 0; i < len(reqOpts); i++ {
+		if err = reqOpts[i](req); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", req.Method, req.URL.RequestURI())
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			fmt.Fprintf(&b, "%s: %s\n", k, redactHeader(k, v))
+		}
+	}
+	if len(data) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", data)
+	}
+	return b.String(), nil
+}
+
+// InvokeURL resolves the pod of the plugin and returns the URL an invoke of
+// the method through a port-forward is sent to, with the Invoker params,
+// without forwarding a port or sending anything. It also returns the kubectl
+// command forwarding the port the URL points at, which is LocalPort, or the
+// remote port of the pod when zero.
+func (inv *Invoker) InvokeURL(pluginID, method string) (string, string, error) {
+	if err := inv.checkProtocol(); err != nil {
+		return "", "", err
+	}
+	if inv.Protocol == ProtocolGRPC {
+		return "", "", &UsageError{errors.New("a URL is only composed for the http protocol")}
+	}
+	client, err := Client()
+	if err != nil {
+		return "", "", err
+	}
+
+	var (
+		namespace, podName, endpoint string
+		remotePort                   int
+	)
+	pf := &PortForward{LocalPort: inv.LocalPort}
+	if inv.Service != nil {
+		namespace = inv.Service.Namespace
+		if podName, remotePort, err = resolveServicePod(client, inv.Service); err != nil {
+			return "", "", err
+		}
+		if pf.LocalPort == 0 {
+			pf.LocalPort = remotePort
+		}
+		endpoint = makeRawEndpoint(inv.scheme(), pf, escapeMethod(method))
+	} else {
+		app, err := inv.selectPod(client, pluginID)
+		if err != nil {
+			return "", "", err
+		}
+		namespace, podName, remotePort = app.Namespace, app.PodName, app.HTTPPort
+		if pf.LocalPort == 0 {
+			pf.LocalPort = remotePort
+		}
+		endpoint = makeEndpoint(inv.scheme(), app, pf, method)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("error creat http request: %w", err)
+	}
+	if err = InvokeAddHTTPParams(inv.Params)(req); err != nil {
+		return "", "", err
+	}
+	forward := fmt.Sprintf("kubectl port-forward -n %s pod/%s %d:%d", namespace, podName, pf.LocalPort, remotePort)
+	return req.URL.String(), forward, nil
+}
@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/term"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// ExecOptions configures the command PluginExec runs.
+type ExecOptions struct {
+	// Pod of the plugin to run the command in, the first running pod if empty.
+	Pod string
+	// Container to run the command in, the app container if empty.
+	Container string
+	// Stdin passes the local stdin to the command.
+	Stdin bool
+	// TTY allocates a terminal for the command, stderr is then merged into stdout.
+	TTY bool
+}
+
+// PluginExec runs command in a container of a pod of the plugin through the
+// exec subresource, wired to the local stdin, stdout and stderr.
+func PluginExec(pluginID string, command []string, opts ExecOptions) error {
+	if len(command) == 0 {
+		return &UsageError{errors.New("no command to run")}
+	}
+	config, client, err := GetKubeConfigClient()
+	if err != nil {
+		return fmt.Errorf("get kube config error: %w", err)
+	}
+	app, err := SelectAppPod(client, pluginID, opts.Pod)
+	if err != nil {
+		return err
+	}
+	container, err := selectContainer(app.pod, opts.Container)
+	if err != nil {
+		return err
+	}
+
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(app.Namespace).
+		Name(app.PodName).
+		SubResource("exec").
+		VersionedParams(&core_v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     opts.Stdin,
+			Stdout:    true,
+			Stderr:    !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+	transport, upgrader, err := spdyRoundTripper(config)
+	if err != nil {
+		return err
+	}
+	executor, err := remotecommand.NewSPDYExecutorForTransports(transport, upgrader, http.MethodPost, req.URL())
+	if err != nil {
+		return fmt.Errorf("error creat executor: %w", err)
+	}
+
+	streamOpts := remotecommand.StreamOptions{Stdout: os.Stdout, Tty: opts.TTY}
+	if opts.Stdin {
+		streamOpts.Stdin = os.Stdin
+	}
+	if !opts.TTY {
+		streamOpts.Stderr = os.Stderr
+		return executor.Stream(streamOpts)
+	}
+
+	// in a terminal the keys go to the remote command as typed, including ^C.
+	fd := int(os.Stdin.Fd())
+	if opts.Stdin && term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("error set terminal raw mode: %w", err)
+		}
+		defer term.Restore(fd, state)
+	}
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		streamOpts.TerminalSizeQueue = &terminalSize{size: &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}}
+	}
+	return executor.Stream(streamOpts)
+}
+
+// ExecExitStatus returns the exit status of the remote command when err
+// reports that it exited non-zero.
+func ExecExitStatus(err error) (int, bool) {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
+// terminalSize reports the size of the local terminal once, when the
+// remote one is set up.
+type terminalSize struct {
+	size *remotecommand.TerminalSize
+}
+
+func (t *terminalSize) Next() *remotecommand.TerminalSize {
+	size := t.size
+	t.size = nil
+	return size
+}
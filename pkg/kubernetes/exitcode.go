@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Exit codes shared by the commands so scripts can tell failures apart.
+const (
+	ExitOK         = 0
+	ExitError      = 1
+	ExitUsage      = 2
+	ExitNotFound   = 3
+	ExitPermission = 4
+)
+
+// ExitCodeHelp documents the exit codes in the help of the commands using them.
+const ExitCodeHelp = `Exit codes:
+  0  success
+  1  generic error
+  2  usage or validation error
+  3  not found
+  4  authentication or permission error`
+
+// UsageError marks an error caused by invalid input rather than by the call itself.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode maps err onto one of the exit codes, based on the HTTP status or
+// kubernetes error behind it.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return ExitUsage
+	}
+	if errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrPodNotFound) || errors.Is(err, ErrPodNotRunning) ||
+		errors.Is(err, ErrServiceNotReady) {
+		return ExitNotFound
+	}
+	if errors.Is(err, ErrPermissionDenied) || errors.Is(err, ErrNotLoggedIn) || errors.Is(err, ErrTokenExpired) {
+		return ExitPermission
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return ExitUsage
+		case http.StatusNotFound:
+			return ExitNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ExitPermission
+		}
+	}
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return ExitNotFound
+	case apierrors.IsUnauthorized(err), apierrors.IsForbidden(err):
+		return ExitPermission
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err):
+		return ExitUsage
+	}
+	return ExitError
+}
@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// Statuses of a ComponentHealthOutput.
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+const (
+	// HealthTimeout is the default bound on the probe of one component.
+	HealthTimeout = 10 * time.Second
+	// HealthConcurrency bounds how many components are probed at once.
+	HealthConcurrency = 4
+)
+
+// ControlPlaneComponents returns the components of the control plane, in
+// the order they are checked.
+func ControlPlaneComponents() []string {
+	return append([]string(nil), controlPlaneApps...)
+}
+
+// ComponentHealthOutput is the health of a component of the control plane.
+type ComponentHealthOutput struct {
+	Component string `csv:"COMPONENT" json:"component"         yaml:"component"`
+	PodName   string `csv:"POD NAME"  json:"pod_name"          yaml:"pod_name"`
+	Namespace string `csv:"NAMESPACE" json:"namespace"         yaml:"namespace"`
+	Status    string `csv:"STATUS"    json:"status"            yaml:"status"`
+	Latency   string `csv:"LATENCY"   json:"latency,omitempty" yaml:"latency,omitempty"`
+	Message   string `csv:"MESSAGE"   json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Healthy reports whether the component is healthy.
+func (c *ComponentHealthOutput) Healthy() bool {
+	return c.Status == HealthHealthy
+}
+
+// ControlPlaneHealth checks the components of the control plane, all of
+// them if none is given: a component is healthy when one of its pods is
+// running and the healthz endpoint of its dapr sidecar answers through a
+// port-forward. Up to concurrency components are probed at once and a probe
+// taking longer than timeout reports its component unhealthy. The results
+// are in the order of the components.
+func ControlPlaneHealth(components []string, concurrency int, timeout time.Duration) ([]ComponentHealthOutput, error) {
+	if len(components) == 0 {
+		components = controlPlaneApps
+	}
+	for _, c := range components {
+		if !contains(controlPlaneApps, c) {
+			return nil, &UsageError{fmt.Errorf("unknown component %q, allowed values are: %s", c, strings.Join(controlPlaneApps, ", "))}
+		}
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if timeout <= 0 {
+		timeout = HealthTimeout
+	}
+
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]ComponentHealthOutput, len(components))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				list[i] = probeComponent(client, components[i], timeout)
+			}
+		}()
+	}
+	for i := range components {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return list, nil
+}
+
+// probeComponent checks the health of a component, giving up after timeout.
+// The lookup of the pods can't be cancelled, a probe past its deadline is
+// left to finish in the background.
+func probeComponent(client k8s.Interface, appID string, timeout time.Duration) ComponentHealthOutput {
+	done := make(chan ComponentHealthOutput, 1)
+	go func() {
+		done <- componentHealth(client, appID, timeout)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case out := <-done:
+		return out
+	case <-timer.C:
+		return ComponentHealthOutput{
+			Component: appID,
+			Status:    HealthUnhealthy,
+			Message:   fmt.Sprintf("no answer within %s", timeout),
+		}
+	}
+}
+
+func componentHealth(client k8s.Interface, appID string, timeout time.Duration) ComponentHealthOutput {
+	out := ComponentHealthOutput{Component: appID, Status: HealthUnhealthy}
+	apps, err := GetAppPods(client, appID)
+	if err != nil {
+		out.Message = err.Error()
+		return out
+	}
+	app := apps[0]
+	out.PodName, out.Namespace = app.PodName, app.Namespace
+	if err = checkPodRunning(app); err != nil {
+		out.Message = err.Error()
+		return out
+	}
+
+	inv := &Invoker{Timeout: timeout, Pod: app.PodName}
+	res, err := inv.RawByPortForward(appID, daprHealthzPath, nil, http.MethodGet)
+	if res != nil && res.Duration > 0 {
+		out.Latency = res.Duration.Round(time.Millisecond).String()
+	}
+	if err != nil {
+		out.Message = err.Error()
+		return out
+	}
+	out.Status = HealthHealthy
+	return out
+}
@@ -33,7 +33,10 @@ import (
 func InitHelmConfig(namespace string, log action.DebugLog) (*action.Configuration, error) {
 	helmConf = &action.Configuration{}
 	flags := &genericclioptions.ConfigFlags{
-		Namespace: &namespace,
+		Namespace:        &namespace,
+		Context:          &Context,
+		Impersonate:      &Impersonate,
+		ImpersonateGroup: &ImpersonateGroups,
 	}
 	err := helmConf.Init(flags, namespace, "secret", log)
 	if err != nil {
@@ -18,35 +18,219 @@ package kubernetes
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dapr/cli/pkg/api"
-	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
+	"github.com/tkeel-io/cli/pkg/print"
+	"golang.org/x/net/http2"
+	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
+// DefaultContentType is the Content-Type of an invoke request when none is set.
+const DefaultContentType = "application/json"
+
+// DefaultIdleConnTimeout is how long an idle connection to the port-forward
+// is kept for reuse when the Invoker sets none.
+const DefaultIdleConnTimeout = 90 * time.Second
+
+const (
+	retryBaseBackoff = 500 * time.Millisecond
+	retryMaxBackoff  = 10 * time.Second
+)
+
+var errInvokeTimeout = errors.New("invoke timed out")
+
+var allowedVerbs = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodDelete,
+	http.MethodPatch,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+// StatusError is returned when the invoked plugin responds with an error status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("response status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Body != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Body)
+	}
+	return msg
+}
+
+// DefaultInvoker is the Invoker used by Invoke and InvokeByPortForward.
+var DefaultInvoker = &Invoker{}
+
+// Invoker holds the settings used when invoking a plugin.
+type Invoker struct {
+	// Timeout bounds how long an invoke attempt may run, zero means no limit.
+	Timeout time.Duration
+	// Retries is how many times a transient port-forward invoke failure is retried.
+	Retries int
+	// RetryOn limits the response status codes retried to these instead of
+	// any 5xx to a request other than POST and PATCH, connection failures
+	// are still retried.
+	RetryOn []int
+	// Address is the comma separated local addresses the port-forward listens on.
+	Address string
+	// LocalPort is the local port the port-forward listens on, zero picks a random one.
+	LocalPort int
+	// ContentType of the request body, DefaultContentType if empty.
+	ContentType string
+	// Accept asks the plugin for a response format, no Accept header is sent if empty.
+	Accept string
+	// Header is added to every request, replacing existing values of the same key.
+	Header http.Header
+	// Params are merged into the query of the invoked method.
+	Params url.Values
+	// Protocol used to talk to the dapr sidecar, ProtocolHTTP if empty.
+	Protocol string
+	// TLS switches the port-forward endpoint to https.
+	TLS bool
+	// Insecure skips verifying the certificate of a TLS endpoint.
+	Insecure bool
+	// CertFile and KeyFile hold the client certificate presented to a TLS
+	// endpoint requiring mTLS, they must be set together and imply TLS.
+	CertFile string
+	KeyFile  string
+	// CACertFile holds the CA certificates the endpoint certificate is
+	// verified against instead of the system pool, it implies TLS.
+	CACertFile string
+	// ServerName is the name the endpoint certificate is verified for, e.g.
+	// the service DNS name of the plugin, as the port-forward address never
+	// matches it. It implies TLS.
+	ServerName string
+	// Pod pins the invoke to the named pod of the plugin, the first running
+	// pod is used if empty.
+	Pod string
+	// Service, when set, sends the requests to a port of a kubernetes service
+	// instead of the dapr sidecar of the plugin, the method being the path of
+	// the request. The plugin id is then ignored.
+	Service *ServiceTarget
+	// WaitReady is how long to wait for a pod of the plugin to be running
+	// and ready before giving up, zero fails right away when none is running.
+	WaitReady time.Duration
+	// PatchType sends a PATCH body as a merge, json or strategic merge patch,
+	// setting the matching Content-Type and checking the body shape.
+	PatchType string
+	// DisableKeepAlive opens a new connection for every request instead of
+	// reusing an idle one.
+	DisableKeepAlive bool
+	// IdleConnTimeout closes connections idle for longer, DefaultIdleConnTimeout if zero.
+	IdleConnTimeout time.Duration
+	// HTTP2 talks HTTP/2 to the endpoint, in cleartext (h2c) unless TLS is used.
+	HTTP2 bool
+	// Trace receives the curl equivalent of every request sent through a
+	// port-forward and the timing of its response, nil disables tracing.
+	Trace io.Writer
+	// Stream receives the body of a successful response through a
+	// port-forward as it arrives, until the plugin closes it or the user
+	// interrupts, instead of InvokeResult.Body. Timeout then only bounds the
+	// wait for the response headers.
+	Stream io.Writer
+}
+
 // Invoke is a command to invoke a remote or local dapr instance.
 func Invoke(pluginID, method string, data []byte, verb string, reqOpts ...RestRequestOption) (string, error) {
-	client, err := Client()
+	return DefaultInvoker.Invoke(pluginID, method, data, verb, reqOpts...)
+}
+
+// Invoke invokes the plugin through the kubernetes apiserver proxy.
+func (inv *Invoker) Invoke(pluginID, method string, data []byte, verb string, reqOpts ...RestRequestOption) (string, error) {
+	res, err := inv.InvokeResult(pluginID, method, data, verb, reqOpts...)
 	if err != nil {
 		return "", err
 	}
+	return res.Body, nil
+}
+
+// InvokeResult is Invoke that also reports the status line and headers of
+// the response, which are set even when the plugin answers with an error.
+func (inv *Invoker) InvokeResult(pluginID, method string, data []byte, verb string, reqOpts ...RestRequestOption) (*InvokeResult, error) {
+	if err := inv.checkPatch(verb, data); err != nil {
+		return nil, err
+	}
 
-	app, err := GetAppPod(client, pluginID)
+	config, client, err := GetKubeConfigClient()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	app, err := inv.selectPod(client, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rest client only hands back the body, record the raw response
+	// on the way through to get at the status line and headers.
+	res := &InvokeResult{}
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &responseRecorder{RoundTripper: rt, result: res}
+	})
+	proxyClient, err := k8s.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error get k8s client: %w", err)
 	}
 
-	return invoke(client.CoreV1().RESTClient(), &app.AppInfo, method, data, verb, reqOpts...)
+	ctx, cancel := inv.newContext()
+	defer cancel()
+
+	reqOpts = append(inv.restRequestOptions(), reqOpts...)
+	start := time.Now()
+	res.Body, err = invoke(ctx, proxyClient.CoreV1().RESTClient(), &app.AppInfo, method, data, verb, reqOpts...)
+	res.Duration, res.Size = time.Since(start), int64(len(res.Body))
+	return res, inv.checkTimeout(err)
+}
+
+// responseRecorder copies the status line and headers of the responses it
+// sees into result.
+type responseRecorder struct {
+	http.RoundTripper
+	result *InvokeResult
 }
 
-func invoke(client rest.Interface, app *AppInfo, method string, data []byte, verb string, reqOpts ...RestRequestOption) (string, error) {
+func (r *responseRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.RoundTripper.RoundTrip(req)
+	if err == nil {
+		r.result.setResponse(resp)
+	}
+	return resp, err
+}
+
+// invoke sends the request to the app through the apiserver proxy of client,
+// which tests replace with an in-process fake.
+func invoke(ctx context.Context, client rest.Interface, app *AppInfo, method string, data []byte, verb string, reqOpts ...RestRequestOption) (string, error) {
+	verb, err := normalizeVerb(verb)
+	if err != nil {
+		return "", err
+	}
+
 	req, err := app.Request(client.Verb(verb), method, data)
 	if err != nil {
 		return "", fmt.Errorf("error get request: %w", err)
@@ -58,8 +242,13 @@ func invoke(client rest.Interface, app *AppInfo, method string, data []byte, ver
 		}
 	}
 
-	result := req.Do(context.TODO())
+	result := req.Do(ctx)
 	rawbody, err := result.Raw()
+	statusCode := http.StatusOK
+	result.StatusCode(&statusCode)
+	if statusCode >= http.StatusBadRequest {
+		return "", &StatusError{StatusCode: statusCode, Body: string(rawbody)}
+	}
 	if err != nil {
 		return "", fmt.Errorf("error get raw: %w", err)
 	}
@@ -73,50 +262,510 @@ func invoke(client rest.Interface, app *AppInfo, method string, data []byte, ver
 
 // InvokeByPortForward is a command to invoke a remote or local dapr instance.
 func InvokeByPortForward(pluginID, method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
-	portForward, err := GetPortforward(pluginID, WithHTTPPort, WithAppPod)
+	return DefaultInvoker.InvokeByPortForward(pluginID, method, data, verb, reqOpts...)
+}
+
+// InvokeResult is the outcome of an invoke through a port-forward.
+type InvokeResult struct {
+	// Body of the response.
+	Body string
+	// LocalPort the port-forward listened on.
+	LocalPort int
+	// Endpoint the request was sent to.
+	Endpoint string
+	// Status line of the HTTP response, e.g. "HTTP/1.1 200 OK".
+	Status string
+	// StatusCode of the HTTP response, zero for gRPC.
+	StatusCode int
+	// Header of the HTTP response.
+	Header http.Header
+	// Size of the response body in bytes, including a streamed one.
+	Size int64
+	// Duration from sending the request to reading the end of the response.
+	Duration time.Duration
+}
+
+func (res *InvokeResult) setResponse(r *http.Response) {
+	res.Status = fmt.Sprintf("%s %s", r.Proto, r.Status)
+	res.StatusCode = r.StatusCode
+	res.Header = r.Header
+}
+
+// InvokeByPortForward invokes the plugin through a port-forward to its dapr sidecar.
+func (inv *Invoker) InvokeByPortForward(pluginID, method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+	res, err := inv.InvokeByPortForwardResult(pluginID, method, data, verb, reqOpts...)
 	if err != nil {
 		return "", err
 	}
-	// initialize port forwarding.
-	if err = portForward.Init(); err == nil {
-		url := makeEndpoint(portForward.App, portForward, method)
-		// initialize port forwarding.
-		// fmt.Println(verb, url)
-		var req *http.Request
-		req, err = http.NewRequest(verb, url, bytes.NewBuffer(data))
-		if err != nil {
-			return "", fmt.Errorf("error creat http request: %w", err)
+	return res.Body, nil
+}
+
+// InvokeByPortForwardResult is InvokeByPortForward that also reports the
+// local port and endpoint used, for callers embedding the invoke.
+func (inv *Invoker) InvokeByPortForwardResult(pluginID, method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (*InvokeResult, error) {
+	return inv.byPortForward(pluginID, method, false, data, verb, reqOpts...)
+}
+
+// RawByPortForward sends a request to an arbitrary path of the dapr HTTP API
+// of the plugin, such as v1.0/metadata or v1.0/state/<store>, through a
+// port-forward to its sidecar.
+func (inv *Invoker) RawByPortForward(pluginID, path string, data []byte, verb string, reqOpts ...HTTPRequestOption) (*InvokeResult, error) {
+	if inv.Protocol == ProtocolGRPC {
+		return nil, &UsageError{errors.New("raw requests only support the http protocol")}
+	}
+	return inv.byPortForward(pluginID, path, true, data, verb, reqOpts...)
+}
+
+// byPortForward validates the request and sends it through a port-forward,
+// retrying transient failures. With raw set, method is a path of the dapr
+// HTTP API rather than a method of the plugin.
+func (inv *Invoker) byPortForward(pluginID, method string, raw bool, data []byte, verb string, reqOpts ...HTTPRequestOption) (*InvokeResult, error) {
+	verb, err := inv.checkRequest(verb, data)
+	if err != nil {
+		return nil, err
+	}
+	httpc, err := inv.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	reqOpts = append(inv.httpRequestOptions(), reqOpts...)
+	return inv.retry(func() (*InvokeResult, bool, error) {
+		return inv.invokeByPortForward(httpc, pluginID, method, raw, data, verb, reqOpts...)
+	})
+}
+
+// checkRequest validates the verb, protocol and patch settings of a request
+// and returns the normalized verb.
+func (inv *Invoker) checkRequest(verb string, data []byte) (string, error) {
+	verb, err := normalizeVerb(verb)
+	if err != nil {
+		return "", err
+	}
+	if err = inv.checkProtocol(); err != nil {
+		return "", err
+	}
+	if err = inv.checkPatch(verb, data); err != nil {
+		return "", err
+	}
+	if err = inv.checkRetryOn(); err != nil {
+		return "", err
+	}
+	return verb, nil
+}
+
+func (inv *Invoker) checkProtocol() error {
+	if inv.Protocol != "" && inv.Protocol != ProtocolHTTP && inv.Protocol != ProtocolGRPC {
+		return &UsageError{fmt.Errorf("invalid protocol %q, allowed values are: %s, %s", inv.Protocol, ProtocolHTTP, ProtocolGRPC)}
+	}
+	if inv.Trace != nil && inv.Protocol == ProtocolGRPC {
+		return &UsageError{errors.New("tracing is only supported with the http protocol")}
+	}
+	if inv.Stream != nil && inv.Protocol == ProtocolGRPC {
+		return &UsageError{errors.New("streaming is only supported with the http protocol")}
+	}
+	if inv.Service != nil && inv.Protocol == ProtocolGRPC {
+		return &UsageError{errors.New("a service can only be invoked with the http protocol")}
+	}
+	if inv.Service != nil && (inv.Pod != "" || inv.WaitReady > 0) {
+		return &UsageError{errors.New("the pod of a service is picked from its ready endpoints, it can't be pinned or waited for")}
+	}
+	if inv.HTTP2 && inv.DisableKeepAlive {
+		return &UsageError{errors.New("HTTP/2 multiplexes requests over one connection, it can't be used with keep-alive disabled")}
+	}
+	return nil
+}
+
+// checkRetryOn validates the RetryOn status codes.
+func (inv *Invoker) checkRetryOn() error {
+	if len(inv.RetryOn) > 0 && inv.Retries == 0 {
+		return &UsageError{errors.New("retrying on status codes needs a number of retries")}
+	}
+	for _, code := range inv.RetryOn {
+		if code < 100 || code > 599 {
+			return &UsageError{fmt.Errorf("invalid status code %d to retry on", code)}
 		}
-		req.Header.Set("Content-Type", "application/json")
+	}
+	return nil
+}
 
-		for i := 0; i < len(reqOpts); i++ {
-			if err = reqOpts[i](req); err != nil {
-				return "", err
-			}
+// retry runs attempt until it succeeds, fails for good or runs out of retries.
+func (inv *Invoker) retry(attempt func() (*InvokeResult, bool, error)) (*InvokeResult, error) {
+	for n := 0; ; n++ {
+		res, retryable, err := attempt()
+		if err == nil || !retryable || n >= inv.Retries {
+			return res, err
 		}
 
-		var httpc http.Client
+		backoff := retryBackoff(n)
+		after, ok := time.Duration(0), false
+		if res != nil && res.Header != nil {
+			after, ok = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+		}
+		switch {
+		case ok && after > retryMaxBackoff:
+			// a far Retry-After would leave the command hanging, wait no longer
+			// than any other retry and say so.
+			backoff = retryMaxBackoff
+			print.WarningStatusEvent(os.Stderr, "Invoke attempt %d/%d failed: %s, retrying in %s instead of the %s asked by Retry-After", n+1, inv.Retries+1, err, backoff, after)
+		case ok:
+			// the plugin told when to come back, always say so.
+			backoff = after
+			print.WarningStatusEvent(os.Stderr, "Invoke attempt %d/%d failed: %s, retrying in %s as asked by Retry-After", n+1, inv.Retries+1, err, backoff)
+		case print.Verbose(print.VerbosityEndpoints):
+			print.WarningStatusEvent(os.Stderr, "Invoke attempt %d/%d failed: %s, retrying in %s", n+1, inv.Retries+1, err, backoff)
+		}
+		if !waitRetry(backoff) {
+			return res, fmt.Errorf("invoke interrupted while waiting to retry: %w", err)
+		}
+	}
+}
 
-		var r *http.Response
-		r, err = httpc.Do(req)
-		if err != nil {
-			return "", fmt.Errorf("error do http request: %w", err)
+// waitRetry waits d before the next attempt, it returns false as soon as the
+// command is interrupted.
+func waitRetry(d time.Duration) bool {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// invokeByPortForward does a single invoke over a new port-forward and reports
+// whether a failure is transient and worth retrying.
+func (inv *Invoker) invokeByPortForward(httpc *http.Client, pluginID, method string, raw bool, data []byte, verb string, reqOpts ...HTTPRequestOption) (*InvokeResult, bool, error) {
+	s, retryable, err := inv.openSession(httpc, pluginID)
+	if err != nil {
+		return nil, retryable, err
+	}
+	// the forward is torn down however the request ends, including on timeout.
+	defer s.Close()
+	return s.do(method, raw, data, verb, reqOpts...)
+}
+
+// logHeaders prints the headers, sorted by key, at header verbosity.
+// Credentials are redacted, including the ones given with -H to invoke and
+// to the websocket upgrade.
+func logHeaders(prefix string, header http.Header) {
+	if !print.Verbose(print.VerbosityHeaders) {
+		return
+	}
+	for _, line := range headerLines(prefix, header) {
+		print.Verbosef(print.VerbosityHeaders, "%s", line)
+	}
+}
+
+// headerLines renders the headers as logged by logHeaders.
+func headerLines(prefix string, header http.Header) []string {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var lines []string
+	for _, k := range keys {
+		for _, v := range header[k] {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", prefix, k, redactHeader(k, v)))
+		}
+	}
+	return lines
+}
+
+// retryableStatus reports whether a response with the status code to a
+// request with verb is worth retrying: the RetryOn codes when set, otherwise
+// any 5xx to an idempotent request. POST and PATCH may have been applied
+// before failing, they are only retried on the codes opted in with RetryOn.
+func (inv *Invoker) retryableStatus(verb string, code int) bool {
+	if len(inv.RetryOn) == 0 {
+		return code >= http.StatusInternalServerError && verb != http.MethodPost && verb != http.MethodPatch
+	}
+	for _, c := range inv.RetryOn {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter returns the delay a Retry-After header asks for, given in
+// seconds or as an HTTP date, and whether the header is valid.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// retryBackoff returns the exponential delay to wait before retrying after attempt.
+func retryBackoff(attempt int) time.Duration {
+	backoff := retryBaseBackoff << attempt
+	if backoff <= 0 || backoff > retryMaxBackoff {
+		return retryMaxBackoff
+	}
+	return backoff
+}
+
+// normalizeVerb upper-cases verb and rejects anything but the standard HTTP methods.
+func normalizeVerb(verb string) (string, error) {
+	v := strings.ToUpper(strings.TrimSpace(verb))
+	for _, allowed := range allowedVerbs {
+		if v == allowed {
+			return v, nil
+		}
+	}
+	return "", &UsageError{fmt.Errorf("invalid HTTP verb %q, allowed values are: %s", verb, strings.Join(allowedVerbs, ", "))}
+}
+
+// restRequestOptions returns the options applying the Invoker settings to a REST request.
+func (inv *Invoker) restRequestOptions() []RestRequestOption {
+	options := []RestRequestOption{InvokeSetRestRequestHeader("Content-Type", inv.contentType())}
+	if inv.Accept != "" {
+		options = append(options, InvokeSetRestRequestHeader("Accept", inv.Accept))
+	}
+	return append(options,
+		InvokeSetRestRequestHeaders(inv.Header),
+		InvokeAddRestRequestParams(inv.Params),
+	)
+}
+
+// httpRequestOptions returns the options applying the Invoker settings to an HTTP request.
+func (inv *Invoker) httpRequestOptions() []HTTPRequestOption {
+	options := []HTTPRequestOption{InvokeSetHTTPHeader("Content-Type", inv.contentType())}
+	if inv.Accept != "" {
+		options = append(options, InvokeSetHTTPHeader("Accept", inv.Accept))
+	}
+	return append(options,
+		InvokeSetHTTPHeaders(inv.Header),
+		InvokeAddHTTPParams(inv.Params),
+	)
+}
+
+// portForwardOptions appends the options applying the Invoker settings to a port-forward.
+func (inv *Invoker) portForwardOptions(options ...PortForwardConfigureOption) []PortForwardConfigureOption {
+	options = append(options, WithProgress)
+	if inv.Address != "" {
+		options = append(options, WithAddress(inv.Address))
+	}
+	if inv.LocalPort != 0 {
+		options = append(options, WithLocalPort(inv.LocalPort))
+	}
+	return options
+}
+
+// selectPod picks the pod of the plugin to invoke, waiting up to WaitReady
+// for it to be ready when set.
+func (inv *Invoker) selectPod(client k8s.Interface, pluginID string) (*AppPod, error) {
+	if inv.WaitReady > 0 {
+		return WaitAppPodReady(context.Background(), client, pluginID, inv.Pod, inv.WaitReady)
+	}
+	app, err := SelectAppPod(client, pluginID, inv.Pod)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPodRunning(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func (inv *Invoker) contentType() string {
+	if pt, ok := patchContentTypes[inv.PatchType]; ok {
+		return string(pt)
+	}
+	if inv.ContentType != "" {
+		return inv.ContentType
+	}
+	return DefaultContentType
+}
+
+func (inv *Invoker) scheme() string {
+	if inv.useTLS() {
+		return "https"
+	}
+	return "http"
+}
+
+// httpClient returns the client used to call the port-forward endpoint.
+func (inv *Invoker) httpClient() (*http.Client, error) {
+	client := &http.Client{Timeout: inv.Timeout}
+	if inv.Stream != nil {
+		// a stream may last as long as the plugin keeps it open.
+		client.Timeout = 0
+	}
+	tlsConfig, err := inv.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout := inv.IdleConnTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleConnTimeout
+	}
+
+	if inv.HTTP2 && !inv.useTLS() {
+		// h2c, HTTP/2 without TLS, needs a transport dialing plain TCP.
+		client.Transport = &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+			IdleConnTimeout: idleTimeout,
 		}
-		defer r.Body.Close()
-		return readResponse(r)
+		return client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// over TLS HTTP/2 is only negotiated with the endpoint when asked for,
+	// the clone would otherwise attempt it whatever inv.HTTP2.
+	transport.TLSClientConfig = tlsConfig
+	transport.ForceAttemptHTTP2 = inv.HTTP2
+	transport.DisableKeepAlives = inv.DisableKeepAlive
+	transport.IdleConnTimeout = idleTimeout
+	if inv.Stream != nil {
+		transport.ResponseHeaderTimeout = inv.Timeout
+	}
+	// the forward leads to a single host, keep as many idle connections
+	// as a session may use.
+	transport.MaxIdleConnsPerHost = transport.MaxIdleConns
+	client.Transport = transport
+	return client, nil
+}
+
+func (inv *Invoker) newContext() (context.Context, context.CancelFunc) {
+	if inv.Timeout > 0 {
+		return context.WithTimeout(context.Background(), inv.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// checkTimeout replaces a deadline error with one naming the configured timeout.
+func (inv *Invoker) checkTimeout(err error) error {
+	if err == nil || inv.Timeout <= 0 {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w after %s", errInvokeTimeout, inv.Timeout)
 	}
+	return err
+}
+
+// Paths of the dapr HTTP API of a sidecar.
+var (
+	daprMetadataPath = fmt.Sprintf("v%s/metadata", api.RuntimeAPIVersion)
+	daprHealthzPath  = fmt.Sprintf("v%s/healthz", api.RuntimeAPIVersion)
+)
+
+// URLOptions are how the sidecar of an app is reached on a port-forward,
+// over http on DefaultAddress when left empty.
+type URLOptions struct {
+	Scheme string
+	Host   string
+}
+
+// url is the URL of path on the port-forward listening on localPort.
+func (o URLOptions) url(localPort int, path string) string {
+	scheme, host := o.Scheme, o.Host
+	if scheme == "" {
+		scheme = "http"
+	}
+	if host == "" {
+		host = DefaultAddress
+	}
+	return localURL(scheme, host, localPort, path)
+}
+
+// InvokeURL is the URL invoking method of the app through the dapr HTTP API
+// of its sidecar forwarded to localPort. Each segment and query parameter of
+// method is escaped, escaped ones being kept as they are.
+func (a *AppPod) InvokeURL(localPort int, method string, opts URLOptions) string {
+	return opts.url(localPort, fmt.Sprintf("v%s/invoke/%s/method/%s", api.RuntimeAPIVersion, a.AppID, escapeMethod(method)))
+}
+
+// MetadataURL is the URL of the metadata of the sidecar of the app, its
+// HTTP port being forwarded to localPort.
+func (a *AppPod) MetadataURL(localPort int, opts URLOptions) string {
+	return opts.url(localPort, daprMetadataPath)
+}
+
+// MetricsURL is the URL of the Prometheus metrics of the sidecar of the app,
+// its metrics port being forwarded to localPort.
+func (a *AppPod) MetricsURL(localPort int, opts URLOptions) string {
+	return opts.url(localPort, "metrics")
+}
 
-	portForward.Stop()
-	return "", err
+// localURL is the URL of path on a port-forward listening on host and localPort.
+func localURL(scheme, host string, localPort int, path string) string {
+	return fmt.Sprintf("%s://%s/%s", scheme, net.JoinHostPort(host, strconv.Itoa(localPort)), strings.TrimPrefix(path, "/"))
 }
 
-func makeEndpoint(app *AppPod, pf *PortForward, method string) string {
-	return fmt.Sprintf("http://127.0.0.1:%s/v%s/invoke/%s/method/%s", fmt.Sprintf("%v", pf.LocalPort), api.RuntimeAPIVersion, app.AppID, method)
+// urlOptions reaches the forward with scheme on its first local address.
+func (pf *PortForward) urlOptions(scheme string) URLOptions {
+	return URLOptions{Scheme: scheme, Host: pf.localHost()}
 }
 
-// not use dapr api.
-func makeWsEndpoint(pf *PortForward, method string) string {
-	return fmt.Sprintf("ws://127.0.0.1:%s/%s", fmt.Sprintf("%v", pf.LocalPort), method)
+func makeEndpoint(scheme string, app *AppPod, pf *PortForward, method string) string {
+	return app.InvokeURL(pf.LocalPort, method, pf.urlOptions(scheme))
+}
+
+// escapeMethod escapes each segment of the path of method, keeping the
+// slashes between them, and each key and value of its query. Escaped
+// segments are kept as they are, e.g. the values filled in by --arg.
+func escapeMethod(method string) string {
+	path, rest := method, ""
+	if i := strings.IndexAny(method, "?#"); i >= 0 {
+		path, rest = method[:i], method[i:]
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if u, err := url.PathUnescape(s); err == nil {
+			s = u
+		}
+		segments[i] = url.PathEscape(s)
+	}
+	path = strings.Join(segments, "/")
+	if !strings.HasPrefix(rest, "?") {
+		return path + rest
+	}
+
+	query, fragment := rest[1:], ""
+	if i := strings.IndexByte(query, '#'); i >= 0 {
+		query, fragment = query[:i], query[i:]
+	}
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		for j, s := range kv {
+			if u, err := url.QueryUnescape(s); err == nil {
+				s = u
+			}
+			kv[j] = url.QueryEscape(s)
+		}
+		pairs[i] = strings.Join(kv, "=")
+	}
+	return path + "?" + strings.Join(pairs, "&") + fragment
+}
+
+func makeRawEndpoint(scheme string, pf *PortForward, path string) string {
+	return pf.urlOptions(scheme).url(pf.LocalPort, path)
 }
 
 func readResponse(response *http.Response) (string, error) {
@@ -124,6 +773,13 @@ func readResponse(response *http.Response) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("error read http response: %w", err)
 	}
+	if rb, err = decodeBody(response.Header.Get("Content-Encoding"), rb); err != nil {
+		return "", err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return "", &StatusError{StatusCode: response.StatusCode, Body: string(rb)}
+	}
 
 	if len(rb) > 0 {
 		return string(rb), nil
@@ -132,61 +788,62 @@ func readResponse(response *http.Response) (string, error) {
 	return "", nil
 }
 
-// WebsocketByPortForward websocket request to the k8s pod.
-func WebsocketByPortForward(pluginID, method string, data []byte) (string, error) {
-	portForward, err := GetPortforward(pluginID, WithAppPort)
+// streamResponse copies the body of the response to w as it arrives, until
+// the plugin closes it or ctx is done, e.g. on Ctrl-C.
+func streamResponse(ctx context.Context, w io.Writer, response *http.Response) (int64, error) {
+	if coding := response.Header.Get("Content-Encoding"); coding != "" && coding != "identity" {
+		// the content codings are undone on the whole body.
+		body, err := readResponse(response)
+		if err != nil {
+			return 0, err
+		}
+		n, err := io.WriteString(w, body)
+		return int64(n), err
+	}
+
+	n, err := io.Copy(w, response.Body)
+	if err != nil && ctx.Err() != nil {
+		print.InfoStatusEvent(os.Stderr, "Stream aborted")
+		return n, nil
+	}
 	if err != nil {
-		return "", err
+		return n, fmt.Errorf("error read http response stream: %w", err)
 	}
+	return n, nil
+}
 
-	// manage termination of port forwarding connection on interrupt
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt)
-	defer signal.Stop(signals)
-	go func() {
-		<-signals
-		os.Exit(0)
-	}()
-
-	// initialize port forwarding
-	if err = portForward.Init(); err == nil {
-		defer portForward.Stop()
-		url := makeWsEndpoint(portForward, method)
-		fmt.Println(url)
-
-		dialer := websocket.Dialer{}
-		connect, resp, err := dialer.Dial(url, nil)
-		if nil != err {
-			fmt.Println(err)
-			return "", errors.Wrap(err, "connect error")
-		}
-		defer resp.Body.Close()
-		defer connect.Close()
-
-		err = connect.WriteMessage(websocket.TextMessage, data)
-		if nil != err {
-			fmt.Println(err)
-			return "", errors.Wrap(err, "websocket write error")
-		}
-
-		for {
-			messageType, messageData, err := connect.ReadMessage()
-			if nil != err {
-				return "", errors.Wrap(err, "websocket read error")
-			}
-			switch messageType {
-			case websocket.TextMessage:
-				fmt.Println(string(messageData))
-			case websocket.BinaryMessage:
-				fmt.Println(messageData)
-			case websocket.CloseMessage:
-			case websocket.PingMessage:
-			case websocket.PongMessage:
-			default:
+// decodeBody undoes the gzip and deflate content codings of a body, last
+// applied first. Bodies without a known coding are returned untouched.
+func decodeBody(encoding string, body []byte) ([]byte, error) {
+	codings := strings.Split(encoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		var (
+			r   io.ReadCloser
+			err error
+		)
+		switch coding {
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(bytes.NewReader(body))
+		case "deflate":
+			// deflate is meant to be zlib wrapped, but some servers send raw deflate.
+			r, err = zlib.NewReader(bytes.NewReader(body))
+			if err != nil {
+				r, err = flate.NewReader(bytes.NewReader(body)), nil
 			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decode %s response: %w", coding, err)
+		}
+		body, err = ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decode %s response: %w", coding, err)
 		}
 	}
-	return "", nil
+	return body, nil
 }
 
 type HTTPRequestOption func(*http.Request) error
@@ -198,6 +855,33 @@ func InvokeSetHTTPHeader(header, val string) HTTPRequestOption {
 	}
 }
 
+// InvokeSetHTTPHeaders sets all the values of header on the request.
+func InvokeSetHTTPHeaders(header http.Header) HTTPRequestOption {
+	return func(r *http.Request) error {
+		for k, vs := range header {
+			r.Header[http.CanonicalHeaderKey(k)] = vs
+		}
+		return nil
+	}
+}
+
+// InvokeAddHTTPParams merges params into the query of the request URL.
+func InvokeAddHTTPParams(params url.Values) HTTPRequestOption {
+	return func(r *http.Request) error {
+		if len(params) == 0 {
+			return nil
+		}
+		query := r.URL.Query()
+		for k, vs := range params {
+			for _, v := range vs {
+				query.Add(k, v)
+			}
+		}
+		r.URL.RawQuery = query.Encode()
+		return nil
+	}
+}
+
 type RestRequestOption func(*rest.Request) error
 
 func InvokeSetRestRequestHeader(header string, val string) RestRequestOption {
@@ -206,3 +890,25 @@ func InvokeSetRestRequestHeader(header string, val string) RestRequestOption {
 		return nil
 	}
 }
+
+// InvokeSetRestRequestHeaders sets all the values of header on the request.
+func InvokeSetRestRequestHeaders(header http.Header) RestRequestOption {
+	return func(r *rest.Request) error {
+		for k, vs := range header {
+			r.SetHeader(k, vs...)
+		}
+		return nil
+	}
+}
+
+// InvokeAddRestRequestParams merges params into the query of the request.
+func InvokeAddRestRequestParams(params url.Values) RestRequestOption {
+	return func(r *rest.Request) error {
+		for k, vs := range params {
+			for _, v := range vs {
+				r.Param(k, v)
+			}
+		}
+		return nil
+	}
+}
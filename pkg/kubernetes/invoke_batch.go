@@ -0,0 +1,125 @@
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// maxBatchLineSize bounds the size of one line of an invoke batch.
+const maxBatchLineSize = 16 * 1024 * 1024
+
+// BatchResult is the outcome of invoking one line of a batch.
+type BatchResult struct {
+	// Line number of the body in the batch, starting at 1.
+	Line int
+	// Result of the invoke, nil when the line was not sent.
+	Result *InvokeResult
+	// Err of the invoke, or the reason the line was not sent.
+	Err error
+
+	data []byte
+}
+
+// InvokeBatch invokes the method once for every newline-delimited JSON
+// object read from r, with up to concurrency requests in flight over the
+// session, started no faster than limiter allows when it is not nil. Blank
+// lines are skipped and lines that are not a JSON object are reported without
+// being sent. fn is called with the result of every line in the order they
+// complete, one at a time; returning false stops reading the batch, the
+// requests already in flight are still reported. InvokeBatch then returns
+// without waiting for r, a read blocked on it is left behind.
+func (s *InvokeSession) InvokeBatch(r io.Reader, method, verb string, concurrency int, limiter *rate.Limiter, fn func(*BatchResult) bool, reqOpts ...HTTPRequestOption) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	lines := make(chan *BatchResult)
+	results := make(chan *BatchResult)
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// once stopped, leave the lines of a reader blocked on r alone.
+			for {
+				var (
+					br *BatchResult
+					ok bool
+				)
+				select {
+				case br, ok = <-lines:
+				case <-stop:
+					return
+				}
+				if !ok {
+					return
+				}
+				if br.Err == nil && limiter != nil {
+					br.Err = limiter.Wait(context.Background())
+				}
+				if br.Err == nil {
+					br.Result, br.Err = s.InvokeResult(method, br.data, verb, reqOpts...)
+				}
+				results <- br
+			}
+		}()
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		readErr <- readBatch(r, lines, stop)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	stopped := false
+	for br := range results {
+		if !fn(br) && !stopped {
+			stopped = true
+			close(stop)
+		}
+	}
+	if stopped {
+		return nil
+	}
+	return <-readErr
+}
+
+// readBatch sends every non blank line of r to lines until r is exhausted or
+// stop is closed.
+func readBatch(r io.Reader, lines chan<- *BatchResult, stop <-chan struct{}) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchLineSize)
+	n := 0
+	for scanner.Scan() {
+		n++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		br := &BatchResult{Line: n, data: append([]byte(nil), data...)}
+		if data[0] != '{' || !json.Valid(data) {
+			br.Err = &UsageError{fmt.Errorf("line %d is not a JSON object", n)}
+		}
+		select {
+		case lines <- br:
+		case <-stop:
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading batch after line %d: %w", n, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Protocols the Invoker can speak to the dapr sidecar.
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// WithGRPCPort forwards the dapr gRPC port of the app.
+func WithGRPCPort(pf *PortForward, app *AppPod) error {
+	pf.RemotePort = app.GRPCPort
+	return nil
+}
+
+func makeGRPCEndpoint(pf *PortForward) string {
+	return net.JoinHostPort(pf.localHost(), strconv.Itoa(pf.LocalPort))
+}
+
+// invokeGRPC calls the method through the dapr InvokeService gRPC API on the
+// forwarded gRPC port and reports whether a failure is worth retrying.
+func (inv *Invoker) invokeGRPC(endpoint string, app *AppPod, method string, data []byte, verb string) (string, bool, error) {
+	ctx, cancel := inv.newContext()
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, endpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		// like over HTTP, an attempt that ran out of time is not retried.
+		err = inv.checkTimeout(fmt.Errorf("error dial grpc: %w", err))
+		return "", !errors.Is(err, errInvokeTimeout), err
+	}
+	defer conn.Close()
+
+	if len(inv.Header) > 0 {
+		md := metadata.MD{}
+		for k, vs := range inv.Header {
+			md.Append(strings.ToLower(k), vs...)
+		}
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	req := &runtimev1pb.InvokeServiceRequest{
+		Id: app.AppID,
+		Message: &commonv1pb.InvokeRequest{
+			Method:      method,
+			Data:        &anypb.Any{Value: data},
+			ContentType: inv.contentType(),
+			HttpExtension: &commonv1pb.HTTPExtension{
+				Verb:        commonv1pb.HTTPExtension_Verb(commonv1pb.HTTPExtension_Verb_value[verb]),
+				Querystring: inv.Params.Encode(),
+			},
+		},
+	}
+	resp, err := runtimev1pb.NewDaprClient(conn).InvokeService(ctx, req)
+	if err != nil {
+		code := status.Code(err)
+		if code == codes.DeadlineExceeded || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", false, inv.checkTimeout(context.DeadlineExceeded)
+		}
+		return "", code == codes.Unavailable, fmt.Errorf("error invoke grpc: %w", err)
+	}
+
+	return string(resp.GetData().GetValue()), false, nil
+}
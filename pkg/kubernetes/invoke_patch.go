@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Patch types the Invoker can send a PATCH body as.
+const (
+	PatchTypeMerge     = "merge"
+	PatchTypeJSON      = "json"
+	PatchTypeStrategic = "strategic"
+)
+
+var patchContentTypes = map[string]types.PatchType{
+	PatchTypeMerge:     types.MergePatchType,
+	PatchTypeJSON:      types.JSONPatchType,
+	PatchTypeStrategic: types.StrategicMergePatchType,
+}
+
+var jsonPatchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+// checkPatch validates the patch type and, for a PATCH request, that data has
+// the shape the patch type expects.
+func (inv *Invoker) checkPatch(verb string, data []byte) error {
+	if inv.PatchType == "" {
+		return nil
+	}
+	if _, ok := patchContentTypes[inv.PatchType]; !ok {
+		return &UsageError{fmt.Errorf("invalid patch type %q, allowed values are: %s, %s, %s", inv.PatchType, PatchTypeMerge, PatchTypeJSON, PatchTypeStrategic)}
+	}
+	if !strings.EqualFold(verb, http.MethodPatch) {
+		return &UsageError{fmt.Errorf("patch type %q only applies to PATCH requests, got %s", inv.PatchType, verb)}
+	}
+	if err := validatePatch(inv.PatchType, data); err != nil {
+		return &UsageError{err}
+	}
+	return nil
+}
+
+func validatePatch(patchType string, data []byte) error {
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return fmt.Errorf("a %s patch needs a body", patchType)
+	}
+
+	if patchType != PatchTypeJSON {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("a %s patch must be a JSON object: %w", patchType, err)
+		}
+		return nil
+	}
+
+	var ops []map[string]interface{}
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return fmt.Errorf("a json patch must be an array of operations: %w", err)
+	}
+	for i, op := range ops {
+		name, _ := op["op"].(string)
+		if !contains(jsonPatchOps, name) {
+			return fmt.Errorf("operation %d: invalid op %q, allowed values are: %s", i, op["op"], strings.Join(jsonPatchOps, ", "))
+		}
+		if _, ok := op["path"].(string); !ok {
+			return fmt.Errorf("operation %d: missing path", i)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
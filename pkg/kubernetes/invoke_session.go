@@ -0,0 +1,209 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+// InvokeSession keeps a port-forward to a plugin open so that several invokes
+// share the forwarded port and the connections of one http.Client, instead of
+// setting up a new forward for every request. Close it when done.
+type InvokeSession struct {
+	inv   *Invoker
+	httpc *http.Client
+	pf    *PortForward
+}
+
+// NewInvokeSession opens an InvokeSession to the plugin with the DefaultInvoker.
+func NewInvokeSession(pluginID string) (*InvokeSession, error) {
+	return DefaultInvoker.NewInvokeSession(pluginID)
+}
+
+// NewInvokeSession forwards a local port to the dapr sidecar of the plugin
+// and returns a session sending requests through it with the Invoker settings.
+func (inv *Invoker) NewInvokeSession(pluginID string) (*InvokeSession, error) {
+	if err := inv.checkProtocol(); err != nil {
+		return nil, err
+	}
+	httpc, err := inv.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	s, _, err := inv.openSession(httpc, pluginID)
+	return s, err
+}
+
+// openSession sets up the port-forward of a session and reports whether a
+// failure is transient and worth retrying.
+func (inv *Invoker) openSession(httpc *http.Client, pluginID string) (*InvokeSession, bool, error) {
+	if inv.Service != nil {
+		portForward, err := GetPortforwardForService(inv.Service, inv.portForwardOptions()...)
+		if err != nil {
+			return nil, false, err
+		}
+		return inv.startSession(httpc, portForward)
+	}
+
+	remotePort := WithHTTPPort
+	if inv.Protocol == ProtocolGRPC {
+		remotePort = WithGRPCPort
+	}
+	pod := inv.Pod
+	if inv.WaitReady > 0 {
+		client, err := Client()
+		if err != nil {
+			return nil, false, err
+		}
+		app, err := inv.selectPod(client, pluginID)
+		if err != nil {
+			return nil, false, err
+		}
+		// forward to the pod found ready rather than picking again.
+		pod = app.PodName
+	}
+	portForward, err := GetPortforwardForPod(pluginID, pod, inv.portForwardOptions(remotePort, WithAppPod)...)
+	if err != nil {
+		return nil, false, err
+	}
+	return inv.startSession(httpc, portForward)
+}
+
+// startSession initializes the port-forward of a session.
+func (inv *Invoker) startSession(httpc *http.Client, portForward *PortForward) (*InvokeSession, bool, error) {
+	// initialize port forwarding.
+	if err := portForward.Init(); err != nil {
+		portForward.Stop()
+		return nil, true, err
+	}
+	// the local port may have been picked at random, say which one; --quiet hides it.
+	print.InfoStatusEvent(os.Stderr, "Forwarding from %s:%d -> %d", portForward.Host, portForward.LocalPort, portForward.RemotePort)
+	return &InvokeSession{inv: inv, httpc: httpc, pf: portForward}, false, nil
+}
+
+// LocalPort returns the local port the session forwards from.
+func (s *InvokeSession) LocalPort() int {
+	return s.pf.LocalPort
+}
+
+// Invoke calls method of the plugin over the session and returns the body
+// of the response.
+func (s *InvokeSession) Invoke(method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+	res, err := s.InvokeResult(method, data, verb, reqOpts...)
+	if err != nil {
+		return "", err
+	}
+	return res.Body, nil
+}
+
+// InvokeResult is Invoke that also reports the local port and endpoint used.
+// Transient failures are retried over the same forward.
+func (s *InvokeSession) InvokeResult(method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (*InvokeResult, error) {
+	verb, err := s.inv.checkRequest(verb, data)
+	if err != nil {
+		return nil, err
+	}
+
+	reqOpts = append(s.inv.httpRequestOptions(), reqOpts...)
+	return s.inv.retry(func() (*InvokeResult, bool, error) {
+		return s.do(method, false, data, verb, reqOpts...)
+	})
+}
+
+// Close stops the port-forward of the session.
+func (s *InvokeSession) Close() {
+	s.pf.Stop()
+}
+
+// do sends a single request over the session and reports whether a failure
+// is transient and worth retrying.
+func (s *InvokeSession) do(method string, raw bool, data []byte, verb string, reqOpts ...HTTPRequestOption) (*InvokeResult, bool, error) {
+	inv, portForward := s.inv, s.pf
+	res := &InvokeResult{LocalPort: portForward.LocalPort}
+	if inv.Protocol == ProtocolGRPC {
+		res.Endpoint = makeGRPCEndpoint(portForward)
+		print.Verbosef(print.VerbosityEndpoints, "Invoking %s", res.Endpoint)
+		start := time.Now()
+		body, retryable, err := inv.invokeGRPC(res.Endpoint, portForward.App, method, data, verb)
+		res.Body, res.Size, res.Duration = body, int64(len(body)), time.Since(start)
+		return res, retryable, err
+	}
+
+	switch {
+	case raw:
+		res.Endpoint = makeRawEndpoint(inv.scheme(), portForward, method)
+	case inv.Service != nil:
+		res.Endpoint = makeRawEndpoint(inv.scheme(), portForward, escapeMethod(method))
+	default:
+		res.Endpoint = makeEndpoint(inv.scheme(), portForward.App, portForward, method)
+	}
+	print.Verbosef(print.VerbosityEndpoints, "Invoking %s %s", verb, res.Endpoint)
+	req, err := http.NewRequest(verb, res.Endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return res, false, fmt.Errorf("error creat http request: %w", err)
+	}
+	for i := 0; i < len(reqOpts); i++ {
+		if err = reqOpts[i](req); err != nil {
+			return res, false, err
+		}
+	}
+
+	logHeaders(">", req.Header)
+
+	if inv.Stream != nil {
+		ctx, stop := signal.NotifyContext(req.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		req = req.WithContext(ctx)
+	}
+	var timer *requestTimer
+	if inv.Trace != nil {
+		fmt.Fprintf(inv.Trace, "* %s\n", curlCommand(req, data))
+		timer = &requestTimer{}
+		req = timer.trace(req)
+	}
+
+	start := time.Now()
+	r, err := s.httpc.Do(req)
+	if err != nil {
+		if timer != nil {
+			timer.report(inv.Trace)
+		}
+		if inv.Stream != nil && req.Context().Err() != nil {
+			return res, false, errors.New("invoke interrupted before the stream started")
+		}
+		err = inv.checkTimeout(fmt.Errorf("error do http request: %w", err))
+		return res, !errors.Is(err, errInvokeTimeout), err
+	}
+	defer r.Body.Close()
+	res.setResponse(r)
+	print.Verbosef(print.VerbosityHeaders, "< %s", r.Status)
+	logHeaders("<", r.Header)
+
+	if inv.Stream != nil && r.StatusCode < http.StatusBadRequest {
+		// part of the stream may have been written, it is not retried.
+		res.Size, err = streamResponse(req.Context(), inv.Stream, r)
+		res.Duration = time.Since(start)
+		if timer != nil {
+			timer.report(inv.Trace)
+		}
+		return res, false, err
+	}
+	res.Body, err = readResponse(r)
+	res.Size, res.Duration = int64(len(res.Body)), time.Since(start)
+	if timer != nil {
+		timer.report(inv.Trace)
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		res.Size = int64(len(statusErr.Body))
+		return res, inv.retryableStatus(req.Method, statusErr.StatusCode), err
+	}
+	return res, false, inv.checkTimeout(err)
+}
@@ -14,8 +14,20 @@ limitations under the License.
 package kubernetes
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,12 +35,74 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	restfake "k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/clientcmd"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utiltesting "k8s.io/client-go/util/testing"
 )
 
+// TestMain points the tests at a fixture kubeconfig, so that the namespace
+// guessed from the current context doesn't depend on the machine.
+func TestMain(m *testing.M) {
+	os.Setenv(clientcmd.RecommendedConfigPathEnvVar, filepath.Join("testdata", "kubeconfig"))
+	os.Exit(m.Run())
+}
+
+func Test_lookupNamespace(t *testing.T) {
+	defer func(ns, ctx string) { Namespace, Context = ns, ctx }(Namespace, Context)
+
+	testCases := []struct {
+		name      string
+		namespace string
+		context   string
+		want      string
+		guessed   bool
+	}{
+		{name: "current context without namespace", want: metav1.NamespaceAll},
+		{name: "context namespace", context: "team", want: "team-ns", guessed: true},
+		{name: "explicit namespace", namespace: "keel-system", context: "team", want: "keel-system"},
+		{name: "all namespaces", namespace: AllNamespaces, context: "team", want: metav1.NamespaceAll},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			Namespace, Context = tc.namespace, tc.context
+			ns, guessed := lookupNamespace()
+			assert.Equal(t, tc.want, ns)
+			assert.Equal(t, tc.guessed, guessed)
+		})
+	}
+}
+
+func Test_kubeConfigErrorChain(t *testing.T) {
+	Kubeconfig = filepath.Join(t.TempDir(), "missing")
+	defer func() { Kubeconfig = "" }()
+	assert.ErrorIs(t, checkKubeconfig(), ErrKubeConfig)
+
+	err := &kubeConfigError{fmt.Errorf("kubeconfig given by --kubeconfig is not readable: %w", os.ErrPermission)}
+	assert.ErrorIs(t, err, ErrKubeConfig)
+	assert.ErrorIs(t, err, os.ErrPermission)
+	assert.Equal(t, "kubeconfig error: kubeconfig given by --kubeconfig is not readable: permission denied", err.Error())
+}
+
+func Test_controlPlaneOutsideNamespace(t *testing.T) {
+	defer func(ns string) { Namespace = ns }(Namespace)
+	Namespace = "plugins"
+	client := fake.NewSimpleClientset(
+		newDaprAppPod("keel-0", "keel-system", "keel", time.Now(), "31234", "3500", "50001"),
+		newDaprAppPod("iothub-0", "iot", "iothub", time.Now(), "31234", "3500", "50001"),
+	)
+
+	keel, err := GetAppPod(client, _pluginKeel)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "keel-system", keel.Namespace)
+	}
+	// -n still scopes the plugins.
+	_, err = GetAppPod(client, "iothub")
+	assert.ErrorIs(t, err, ErrAppNotFound)
+}
+
 func newDaprAppPod(name string, namespace string, appName string, creationTime time.Time, appPort string, httpPort string, grpcPort string) *v1.Pod {
 	return &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -136,6 +210,8 @@ func Test_getAppInfo(t *testing.T) {
 			if tc.errorExpected {
 				assert.Error(t, err, "expected an error")
 				assert.Equal(t, tc.errString, err.Error(), "expected error strings to match")
+				assert.ErrorIs(t, err, ErrAppNotFound)
+				assert.Equal(t, ExitNotFound, ExitCode(err))
 			} else {
 				assert.NoError(t, err, "expected no error")
 				assert.Equal(t, tc.want, &appInfo.AppInfo, "expected appInfo to match")
@@ -144,6 +220,26 @@ func Test_getAppInfo(t *testing.T) {
 	}
 }
 
+func Test_getAppPodAllNamespaces(t *testing.T) {
+	defer func(ns string) { Namespace = ns }(Namespace)
+	Namespace = AllNamespaces
+
+	client := fake.NewSimpleClientset(
+		newDaprAppPod("single-0", "ns1", "single", time.Now(), "8080", "3500", "50001"),
+		newDaprAppPod("shared-0", "ns1", "shared", time.Now(), "8080", "3500", "50001"),
+		newDaprAppPod("shared-0", "ns2", "shared", time.Now(), "8080", "3500", "50001"),
+	)
+
+	app, err := GetAppPod(client, "single")
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", app.Namespace)
+
+	_, err = GetAppPod(client, "shared")
+	assert.ErrorIs(t, err, ErrAmbiguousApp)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	assert.Contains(t, err.Error(), "ns1/shared-0, ns2/shared-0")
+}
+
 func Test_invoke(t *testing.T) {
 	app := &AppInfo{
 		AppID: "testAppID", AppPort: 8080, HTTPPort: 3500, GRPCPort: 50001, PodName: "testAppPod", Namespace: "testAppNameSpace",
@@ -192,6 +288,50 @@ func Test_invoke(t *testing.T) {
 				"namespaces/testAppNameSpace/pods/testAppPod:8080/proxy/" +
 				"hello?abc=123&cdr=345#abb=aaa",
 		},
+		{
+			name:          "put request",
+			errorExpected: false,
+			errString:     "",
+			method:        "hello",
+			verb:          "PUT",
+			data:          []byte(`{"name":"put"}`),
+			URLExpected: "https://localhost/api/v1/" +
+				"namespaces/testAppNameSpace/pods/testAppPod:8080/proxy/" +
+				"hello",
+		},
+		{
+			name:          "patch request",
+			errorExpected: false,
+			errString:     "",
+			method:        "hello",
+			verb:          "PATCH",
+			data:          []byte(`{"name":"patch"}`),
+			URLExpected: "https://localhost/api/v1/" +
+				"namespaces/testAppNameSpace/pods/testAppPod:8080/proxy/" +
+				"hello",
+		},
+		{
+			name:          "delete request with body",
+			errorExpected: false,
+			errString:     "",
+			method:        "hello",
+			verb:          "DELETE",
+			data:          []byte(`{"ids":[1,2]}`),
+			URLExpected: "https://localhost/api/v1/" +
+				"namespaces/testAppNameSpace/pods/testAppPod:8080/proxy/" +
+				"hello",
+		},
+		{
+			name:          "escaped method",
+			errorExpected: false,
+			errString:     "",
+			method:        "say hello/héllo?name=a b",
+			verb:          "GET",
+			data:          nil,
+			URLExpected: "https://localhost/api/v1/" +
+				"namespaces/testAppNameSpace/pods/testAppPod:8080/proxy/" +
+				"say%20hello/h%C3%A9llo?name=a+b",
+		},
 		{
 			name:          "post request",
 			errorExpected: false,
@@ -213,7 +353,7 @@ func Test_invoke(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			_, err = invoke(client, app, tc.method, tc.data, tc.verb)
+			_, err = invoke(context.TODO(), client, app, tc.method, tc.data, tc.verb)
 			if tc.errorExpected {
 				assert.Error(t, err, "expected an error")
 				assert.Equal(t, tc.errString, err.Error(), "expected error strings to match")
@@ -226,6 +366,341 @@ func Test_invoke(t *testing.T) {
 	}
 }
 
+func Test_escapeMethod(t *testing.T) {
+	testCases := []struct {
+		name   string
+		method string
+		want   string
+	}{
+		{name: "plain", method: "v1/users", want: "v1/users"},
+		{name: "spaces", method: "say hello/to me", want: "say%20hello/to%20me"},
+		{name: "unicode", method: "users/héllo/中", want: "users/h%C3%A9llo/%E4%B8%AD"},
+		{name: "already escaped", method: "users/a%2Fb/say%20hi", want: "users/a%2Fb/say%20hi"},
+		{name: "stray percent", method: "rate/100%", want: "rate/100%25"},
+		{name: "query", method: "users?name=a b&tag=é&flag", want: "users?name=a+b&tag=%C3%A9&flag"},
+		{name: "query and fragment", method: "hello?abc=123&cdr=345#abb=aaa", want: "hello?abc=123&cdr=345#abb=aaa"},
+		{name: "escaped query", method: "users?q=%E4%B8%AD&r=a+b", want: "users?q=%E4%B8%AD&r=a+b"},
+		{name: "slashes kept", method: "/a//b/", want: "/a//b/"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, escapeMethod(tc.method))
+		})
+	}
+}
+
+func Test_appPodURLs(t *testing.T) {
+	app := &AppPod{AppInfo: AppInfo{AppID: "keel", PodName: "keel-0", Namespace: "keel-system"}}
+
+	testCases := []struct {
+		name   string
+		method string
+		want   string
+	}{
+		{name: "plain", method: "v1/users", want: "http://127.0.0.1:3500/v1.0/invoke/keel/method/v1/users"},
+		{name: "escaped", method: "users/héllo?name=a b", want: "http://127.0.0.1:3500/v1.0/invoke/keel/method/users/h%C3%A9llo?name=a+b"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, app.InvokeURL(3500, tc.method, URLOptions{}))
+		})
+	}
+
+	assert.Equal(t, "https://127.0.0.1:3500/v1.0/invoke/keel/method/v1/users", makeEndpoint("https", app, &PortForward{LocalPort: 3500}, "v1/users"))
+	assert.Equal(t, "http://127.0.0.1:3500/v1.0/metadata", app.MetadataURL(3500, URLOptions{}))
+	assert.Equal(t, "https://[::1]:3500/v1.0/metadata", app.MetadataURL(3500, URLOptions{Scheme: "https", Host: "::1"}))
+	assert.Equal(t, "http://[::1]:9090/metrics", app.MetricsURL(9090, (&PortForward{Host: "::1"}).urlOptions("http")))
+	assert.Equal(t, "http://127.0.0.1:3500/v1.0/invoke/keel/method/", app.InvokeURL(3500, "", URLOptions{}))
+}
+
+func Test_invokeStatusError(t *testing.T) {
+	app := &AppInfo{
+		AppID: "testAppID", AppPort: 8080, HTTPPort: 3500, GRPCPort: 50001, PodName: "testAppPod", Namespace: "testAppNameSpace",
+	}
+
+	testServer, _ := testServerEnv(t, 404)
+	defer testServer.Close()
+	client, err := restClient(testServer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = invoke(context.TODO(), client, app, "hello", nil, "GET")
+	var statusErr *StatusError
+	assert.ErrorAs(t, err, &statusErr, "expected a status error")
+	assert.Equal(t, 404, statusErr.StatusCode)
+}
+
+// recordedRequest is a request received by a fakeRESTClient.
+type recordedRequest struct {
+	Verb   string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   string
+}
+
+// fakeRESTClient is an in-process rest.Interface answering every request
+// with a canned status and body, recording the requests it receives.
+type fakeRESTClient struct {
+	*restfake.RESTClient
+	Requests []recordedRequest
+}
+
+func newFakeRESTClient(statusCode int, body string) *fakeRESTClient {
+	c := &fakeRESTClient{}
+	c.RESTClient = &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         v1.SchemeGroupVersion,
+		VersionedAPIPath:     "/api/v1",
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			recorded := recordedRequest{Verb: req.Method, Path: req.URL.Path, Query: req.URL.Query(), Header: req.Header}
+			if req.Body != nil {
+				data, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				recorded.Body = string(data)
+			}
+			c.Requests = append(c.Requests, recorded)
+			return &http.Response{
+				StatusCode: statusCode,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+	return c
+}
+
+func Test_invokeFakeClient(t *testing.T) {
+	app := &AppInfo{
+		AppID: "testAppID", AppPort: 8080, HTTPPort: 3500, GRPCPort: 50001, PodName: "testAppPod", Namespace: "testAppNameSpace",
+	}
+	proxyPath := "/api/v1/namespaces/testAppNameSpace/pods/testAppPod:8080/proxy/"
+
+	testCases := []struct {
+		name       string
+		verb       string
+		method     string
+		data       []byte
+		reqOpts    []RestRequestOption
+		statusCode int
+		respBody   string
+		wantVerb   string
+		wantPath   string
+		wantQuery  url.Values
+		wantBody   string
+		wantHeader http.Header
+		want       string
+		wantErr    string
+		wantExit   int
+	}{
+		{
+			name: "get", verb: "GET", method: "v1/users",
+			statusCode: 200, respBody: `{"users":[]}`,
+			wantVerb: "GET", wantPath: proxyPath + "v1/users", want: `{"users":[]}`,
+		},
+		{
+			name: "lower case verb", verb: "post", method: "v1/users", data: []byte(`{"name":"a"}`),
+			statusCode: 201, respBody: `{"id":1}`,
+			wantVerb: "POST", wantPath: proxyPath + "v1/users", wantBody: `{"name":"a"}`, want: `{"id":1}`,
+		},
+		{
+			name: "query", verb: "GET", method: "v1/users?page=2&tag=a b",
+			statusCode: 200,
+			wantVerb:   "GET", wantPath: proxyPath + "v1/users", wantQuery: url.Values{"page": {"2"}, "tag": {"a b"}},
+		},
+		{
+			name: "escaped path", verb: "DELETE", method: "v1/users/héllo", data: []byte(`{"force":true}`),
+			statusCode: 204,
+			wantVerb:   "DELETE", wantPath: proxyPath + "v1/users/héllo", wantBody: `{"force":true}`,
+		},
+		{
+			name: "request header", verb: "PUT", method: "v1/users/1", data: []byte(`{}`),
+			reqOpts:    []RestRequestOption{InvokeSetRestRequestHeader("X-Tenant", "t1")},
+			statusCode: 200,
+			wantVerb:   "PUT", wantPath: proxyPath + "v1/users/1", wantBody: `{}`,
+			wantHeader: http.Header{"X-Tenant": {"t1"}},
+		},
+		{
+			name: "invalid verb", verb: "FETCH", method: "v1/users",
+			wantErr: `invalid HTTP verb "FETCH"`, wantExit: ExitUsage,
+		},
+		{
+			name: "not found", verb: "GET", method: "v1/users/2",
+			statusCode: 404, respBody: "user not found",
+			wantVerb: "GET", wantPath: proxyPath + "v1/users/2",
+			wantErr: "response status 404 Not Found: user not found", wantExit: ExitNotFound,
+		},
+		{
+			name: "forbidden", verb: "GET", method: "v1/users",
+			statusCode: 403,
+			wantVerb:   "GET", wantPath: proxyPath + "v1/users",
+			wantErr: "response status 403 Forbidden", wantExit: ExitPermission,
+		},
+		{
+			name: "server error", verb: "POST", method: "v1/users", data: []byte(`{}`),
+			statusCode: 500, respBody: "boom",
+			wantVerb: "POST", wantPath: proxyPath + "v1/users", wantBody: `{}`,
+			wantErr: "response status 500 Internal Server Error: boom", wantExit: ExitError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newFakeRESTClient(tc.statusCode, tc.respBody)
+			got, err := invoke(context.TODO(), client, app, tc.method, tc.data, tc.verb, tc.reqOpts...)
+			if tc.wantErr != "" {
+				assert.Error(t, err, "expected an error")
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Equal(t, tc.wantExit, ExitCode(err))
+			} else {
+				assert.NoError(t, err, "expected no error")
+				assert.Equal(t, tc.want, got)
+			}
+
+			if tc.wantVerb == "" {
+				assert.Empty(t, client.Requests, "expected no request sent")
+				return
+			}
+			if !assert.Len(t, client.Requests, 1) {
+				return
+			}
+			req := client.Requests[0]
+			assert.Equal(t, tc.wantVerb, req.Verb)
+			assert.Equal(t, tc.wantPath, req.Path)
+			assert.Equal(t, tc.wantBody, req.Body)
+			for k, v := range tc.wantQuery {
+				assert.Equal(t, v, req.Query[k], "query parameter %s", k)
+			}
+			for k := range tc.wantHeader {
+				assert.Equal(t, tc.wantHeader.Get(k), req.Header.Get(k), "header %s", k)
+			}
+		})
+	}
+}
+
+func Test_sessionRequestBody(t *testing.T) {
+	for _, verb := range []string{"POST", "PUT", "PATCH", "DELETE"} {
+		t.Run(verb, func(t *testing.T) {
+			testServer, fakeHandler := testServerEnv(t, 200)
+			defer testServer.Close()
+			s := &InvokeSession{
+				inv:   &Invoker{},
+				httpc: testServer.Client(),
+				pf: &PortForward{
+					LocalPort: testServer.Listener.Addr().(*net.TCPAddr).Port,
+					App:       &AppPod{AppInfo: AppInfo{AppID: "testAppID"}},
+				},
+			}
+
+			data := `{"verb":"` + verb + `"}`
+			_, err := s.InvokeResult("hello", []byte(data), verb)
+			assert.NoError(t, err, "expected no error")
+			assert.Equal(t, verb, fakeHandler.RequestReceived.Method)
+			assert.Equal(t, data, fakeHandler.RequestBody)
+		})
+	}
+}
+
+func Test_normalizeVerb(t *testing.T) {
+	testCases := []struct {
+		name          string
+		verb          string
+		want          string
+		errorExpected bool
+	}{
+		{name: "upper case", verb: "GET", want: "GET"},
+		{name: "lower case", verb: "post", want: "POST"},
+		{name: "mixed case with spaces", verb: " Patch ", want: "PATCH"},
+		{name: "typo", verb: "GTE", errorExpected: true},
+		{name: "empty", verb: "", errorExpected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verb, err := normalizeVerb(tc.verb)
+			if tc.errorExpected {
+				assert.Error(t, err, "expected an error")
+				assert.Contains(t, err.Error(), "GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS")
+			} else {
+				assert.NoError(t, err, "expected no error")
+				assert.Equal(t, tc.want, verb)
+			}
+		})
+	}
+}
+
+func Test_checkPatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		patchType     string
+		verb          string
+		data          string
+		errorExpected bool
+	}{
+		{name: "no patch type", verb: "POST", data: "[]"},
+		{name: "merge patch", patchType: "merge", verb: "PATCH", data: `{"level":"debug"}`},
+		{name: "strategic patch", patchType: "strategic", verb: "patch", data: `{"spec":{}}`},
+		{name: "json patch", patchType: "json", verb: "PATCH", data: `[{"op":"replace","path":"/level","value":"debug"}]`},
+		{name: "json patch not an array", patchType: "json", verb: "PATCH", data: `{"op":"replace"}`, errorExpected: true},
+		{name: "json patch bad op", patchType: "json", verb: "PATCH", data: `[{"op":"set","path":"/level"}]`, errorExpected: true},
+		{name: "json patch missing path", patchType: "json", verb: "PATCH", data: `[{"op":"remove"}]`, errorExpected: true},
+		{name: "merge patch not an object", patchType: "merge", verb: "PATCH", data: `[]`, errorExpected: true},
+		{name: "empty body", patchType: "merge", verb: "PATCH", errorExpected: true},
+		{name: "not a PATCH", patchType: "merge", verb: "POST", data: `{}`, errorExpected: true},
+		{name: "unknown patch type", patchType: "apply", verb: "PATCH", data: `{}`, errorExpected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inv := &Invoker{PatchType: tc.patchType}
+			err := inv.checkPatch(tc.verb, []byte(tc.data))
+			if tc.errorExpected {
+				assert.Error(t, err, "expected an error")
+				assert.Equal(t, ExitUsage, ExitCode(err))
+			} else {
+				assert.NoError(t, err, "expected no error")
+			}
+		})
+	}
+}
+
+func Test_decodeBody(t *testing.T) {
+	compress := func(w io.WriteCloser, buf *bytes.Buffer) []byte {
+		w.Write([]byte(`{"ok":true}`))
+		w.Close()
+		return buf.Bytes()
+	}
+	var gz, zl, fl bytes.Buffer
+	fw, _ := flate.NewWriter(&fl, flate.DefaultCompression)
+
+	testCases := []struct {
+		name          string
+		encoding      string
+		body          []byte
+		errorExpected bool
+	}{
+		{name: "not encoded", body: []byte(`{"ok":true}`)},
+		{name: "identity", encoding: "identity", body: []byte(`{"ok":true}`)},
+		{name: "gzip", encoding: "gzip", body: compress(gzip.NewWriter(&gz), &gz)},
+		{name: "zlib deflate", encoding: "Deflate", body: compress(zlib.NewWriter(&zl), &zl)},
+		{name: "raw deflate", encoding: "deflate", body: compress(fw, &fl)},
+		{name: "malformed gzip", encoding: "gzip", body: []byte(`{"ok":true}`), errorExpected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := decodeBody(tc.encoding, tc.body)
+			if tc.errorExpected {
+				assert.Error(t, err, "expected an error")
+				assert.Contains(t, err.Error(), tc.encoding)
+			} else {
+				assert.NoError(t, err, "expected no error")
+				assert.Equal(t, `{"ok":true}`, string(body))
+			}
+		})
+	}
+}
+
 func testServerEnv(t *testing.T, statusCode int) (*httptest.Server, *utiltesting.FakeHandler) {
 	t.Helper()
 	fakeHandler := utiltesting.FakeHandler{
@@ -253,3 +728,105 @@ func restClient(testServer *httptest.Server) (*rest.RESTClient, error) {
 	}
 	return c, nil
 }
+
+func Test_readBatch(t *testing.T) {
+	input := bytes.NewBufferString("{\"a\":1}\n\n  [1]\n{bad\n {\"b\":2} \n")
+	lines := make(chan *BatchResult, 10)
+	assert.NoError(t, readBatch(input, lines, make(chan struct{})))
+	close(lines)
+
+	var got []*BatchResult
+	for br := range lines {
+		got = append(got, br)
+	}
+	assert.Len(t, got, 4)
+	assert.Equal(t, []int{1, 3, 4, 5}, []int{got[0].Line, got[1].Line, got[2].Line, got[3].Line})
+	assert.NoError(t, got[0].Err)
+	assert.Equal(t, `{"a":1}`, string(got[0].data))
+	assert.EqualError(t, got[1].Err, "line 3 is not a JSON object")
+	assert.EqualError(t, got[2].Err, "line 4 is not a JSON object")
+	assert.NoError(t, got[3].Err)
+	assert.Equal(t, `{"b":2}`, string(got[3].data))
+
+	stop := make(chan struct{})
+	close(stop)
+	assert.NoError(t, readBatch(bytes.NewBufferString("{}\n{}\n"), make(chan *BatchResult), stop))
+}
+
+func Test_InvokeBatchStopWithBlockedReader(t *testing.T) {
+	// the writer never closes, as stdin left open by the caller.
+	r, w := io.Pipe()
+	defer w.Close()
+	go w.Write([]byte("[1]\n")) //nolint:errcheck
+
+	done := make(chan error, 1)
+	go func() {
+		// the line is not a JSON object, so it is reported without being sent.
+		done <- (&InvokeSession{}).InvokeBatch(r, "v1/users", http.MethodPost, 2, nil, func(br *BatchResult) bool {
+			return br.Err == nil
+		})
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("InvokeBatch waited for the blocked reader after being stopped")
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 11, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "empty", value: "", ok: false},
+		{name: "seconds", value: "120", want: 2 * time.Minute, ok: true},
+		{name: "zero", value: "0", want: 0, ok: true},
+		{name: "negative", value: "-1", ok: false},
+		{name: "http date", value: "Mon, 01 Nov 2021 10:00:30 GMT", want: 30 * time.Second, ok: true},
+		{name: "past date", value: "Mon, 01 Nov 2021 09:00:00 GMT", want: 0, ok: true},
+		{name: "garbage", value: "soon", ok: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_retryableStatus(t *testing.T) {
+	inv := &Invoker{}
+	assert.True(t, inv.retryableStatus("GET", 503))
+	assert.True(t, inv.retryableStatus("PUT", 500))
+	assert.False(t, inv.retryableStatus("GET", 429))
+	assert.False(t, inv.retryableStatus("POST", 503))
+	assert.False(t, inv.retryableStatus("PATCH", 500))
+
+	inv.RetryOn = []int{429, 503}
+	assert.True(t, inv.retryableStatus("GET", 429))
+	assert.True(t, inv.retryableStatus("POST", 503))
+	assert.False(t, inv.retryableStatus("GET", 500))
+}
+
+func Test_headerLines(t *testing.T) {
+	// headers as parsed from the -H flags of tkeel websocket and invoke.
+	header := http.Header{
+		"Authorization":          {"Bearer secret"},
+		"Cookie":                 {"session=secret"},
+		"Sec-Websocket-Protocol": {"mqtt"},
+		"X-Tenant":               {"t1"},
+		"authorization":          {"Bearer lower"},
+	}
+	assert.Equal(t, []string{
+		"> Authorization: <redacted>",
+		"> Cookie: <redacted>",
+		"> Sec-Websocket-Protocol: mqtt",
+		"> X-Tenant: t1",
+		"> authorization: <redacted>",
+	}, headerLines(">", header))
+}
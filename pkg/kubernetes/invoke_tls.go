@@ -0,0 +1,52 @@
+package kubernetes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// useTLS reports whether the port-forward endpoint is called over https.
+func (inv *Invoker) useTLS() bool {
+	return inv.TLS || inv.CertFile != "" || inv.CACertFile != "" || inv.ServerName != ""
+}
+
+// tlsConfig builds the TLS settings of the port-forward endpoint, nil when
+// the defaults do.
+func (inv *Invoker) tlsConfig() (*tls.Config, error) {
+	if (inv.CertFile == "") != (inv.KeyFile == "") {
+		return nil, &UsageError{errors.New("a client certificate and key must be given together")}
+	}
+	if !inv.useTLS() || (!inv.Insecure && inv.CertFile == "" && inv.CACertFile == "" && inv.ServerName == "") {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: inv.ServerName}
+	if inv.Insecure {
+		// the endpoint is a local port-forward, so its certificate never matches 127.0.0.1.
+		config.InsecureSkipVerify = true //nolint:gosec
+	}
+
+	if inv.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(inv.CertFile, inv.KeyFile)
+		if err != nil {
+			return nil, &UsageError{fmt.Errorf("error load client certificate %s and key %s: %w", inv.CertFile, inv.KeyFile, err)}
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if inv.CACertFile != "" {
+		pem, err := ioutil.ReadFile(inv.CACertFile)
+		if err != nil {
+			return nil, &UsageError{fmt.Errorf("error read CA certificate %s: %w", inv.CACertFile, err)}
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, &UsageError{fmt.Errorf("no CA certificate found in %s", inv.CACertFile)}
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -31,7 +31,6 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"sigs.k8s.io/yaml"
 
-	dapr "github.com/dapr/cli/pkg/kubernetes"
 	"github.com/dapr/cli/pkg/print"
 	"github.com/pkg/errors"
 	helm "helm.sh/helm/v3/pkg/action"
@@ -464,7 +463,7 @@ func loadInstallConfig(config InitConfiguration, upgrade bool) (*kitconfig.Insta
 }
 
 func createNamespace(namespace string) error {
-	_, client, err := dapr.GetKubeConfigClient()
+	_, client, err := GetKubeConfigClient()
 	if err != nil {
 		return fmt.Errorf("can't connect to a Kubernetes cluster: %w", err)
 	}
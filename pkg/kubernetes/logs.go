@@ -0,0 +1,134 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/tkeel-io/cli/pkg/print"
+	core_v1 "k8s.io/api/core/v1"
+)
+
+const (
+	daprSidecarContainer = "daprd"
+	// defaultContainerAnnotation names the container kubectl defaults to.
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+)
+
+// LogsOptions selects the logs PluginLogs streams.
+type LogsOptions struct {
+	// Pod of the plugin to read the logs of, the first running pod if empty.
+	Pod string
+	// Container to read the logs of, the app container if empty.
+	Container string
+	// Sidecar reads the logs of the dapr sidecar instead, whatever the name
+	// of its container.
+	Sidecar bool
+	// Follow keeps streaming new lines until ctx is done.
+	Follow bool
+	// Tail is how many of the last lines to show, a negative value shows all.
+	Tail int64
+}
+
+// PluginLogs streams the container logs of a pod of the plugin to w.
+func PluginLogs(ctx context.Context, pluginID string, opts LogsOptions, w io.Writer) error {
+	client, err := Client()
+	if err != nil {
+		return err
+	}
+	app, err := SelectAppPod(client, pluginID, opts.Pod)
+	if err != nil {
+		return err
+	}
+	var container string
+	if opts.Sidecar {
+		container, err = sidecarContainer(app.pod)
+	} else {
+		container, err = selectContainer(app.pod, opts.Container)
+	}
+	if err != nil {
+		return err
+	}
+
+	logOpts := &core_v1.PodLogOptions{Container: container, Follow: opts.Follow}
+	if opts.Tail >= 0 {
+		logOpts.TailLines = &opts.Tail
+	}
+	stream, err := client.CoreV1().Pods(app.Namespace).GetLogs(app.PodName, logOpts).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("error get logs of %s/%s: %w", app.PodName, container, err)
+	}
+	defer stream.Close()
+
+	if _, err = io.Copy(w, stream); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("error read logs of %s/%s: %w", app.PodName, container, err)
+	}
+	return nil
+}
+
+// selectContainer returns the container of the pod named name. Without a
+// name it is the app container, the one next to the dapr sidecar. When the pod
+// runs several besides the sidecar, the default-container annotation or the
+// first one is used and the others are listed.
+func selectContainer(p *DaprPod, name string) (string, error) {
+	names := make([]string, 0, len(p.Spec.Containers))
+	for _, c := range p.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	if name != "" {
+		if !contains(names, name) {
+			return "", &UsageError{fmt.Errorf("container %s not found in pod %s, choose one of: %s", name, p.Name, strings.Join(names, ", "))}
+		}
+		return name, nil
+	}
+
+	apps := make([]string, 0, len(names))
+	for _, c := range p.Spec.Containers {
+		if !isDaprSidecar(c) {
+			apps = append(apps, c.Name)
+		}
+	}
+	switch {
+	case len(apps) == 0:
+		return sidecarContainer(p)
+	case len(apps) == 1:
+		return apps[0], nil
+	}
+
+	container := apps[0]
+	if c := p.Annotations[defaultContainerAnnotation]; contains(apps, c) {
+		container = c
+	}
+	print.InfoStatusEvent(os.Stderr, "Pod %s has several containers, using %s (choose one of %s with --container)", p.Name, container, strings.Join(names, ", "))
+	return container, nil
+}
+
+// sidecarContainer returns the name of the dapr sidecar container of the pod.
+func sidecarContainer(p *DaprPod) (string, error) {
+	for _, c := range p.Spec.Containers {
+		if isDaprSidecar(c) {
+			return c.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no dapr sidecar container found in pod %s", p.Name)
+}
+
+// isDaprSidecar reports whether the container runs daprd. The injector names
+// it daprd, a sidecar added or renamed by hand is recognized by its daprd
+// image or command.
+func isDaprSidecar(c core_v1.Container) bool {
+	if c.Name == daprSidecarContainer {
+		return true
+	}
+	image := c.Image
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		image = image[i+1:]
+	}
+	if image == daprSidecarContainer || strings.HasPrefix(image, daprSidecarContainer+":") || strings.HasPrefix(image, daprSidecarContainer+"@") {
+		return true
+	}
+	return len(c.Command) > 0 && path.Base(c.Command[0]) == daprSidecarContainer
+}
@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+const metadataTimeout = 30 * time.Second
+
+// Kinds of a MetadataEntryOutput.
+const (
+	MetadataComponent    = "component"
+	MetadataSubscription = "subscription"
+	MetadataActor        = "actor"
+)
+
+// DaprMetadata is what the metadata endpoint of a dapr sidecar reports.
+type DaprMetadata struct {
+	ID             string             `json:"id"                      yaml:"id"`
+	RuntimeVersion string             `json:"runtimeVersion"          yaml:"runtimeVersion"`
+	Components     []DaprComponent    `json:"components"              yaml:"components"`
+	Subscriptions  []DaprSubscription `json:"subscriptions,omitempty" yaml:"subscriptions,omitempty"`
+	Actors         []DaprActor        `json:"actors,omitempty"        yaml:"actors,omitempty"`
+	Extended       map[string]string  `json:"extended,omitempty"      yaml:"extended,omitempty"`
+}
+
+// DaprComponent is a component loaded by a dapr sidecar.
+type DaprComponent struct {
+	Name         string   `json:"name"                   yaml:"name"`
+	Type         string   `json:"type"                   yaml:"type"`
+	Version      string   `json:"version"                yaml:"version"`
+	Capabilities []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+}
+
+// DaprSubscription is a pub/sub subscription of the app of a dapr sidecar.
+type DaprSubscription struct {
+	PubsubName      string                 `json:"pubsubname"                yaml:"pubsubname"`
+	Topic           string                 `json:"topic"                     yaml:"topic"`
+	DeadLetterTopic string                 `json:"deadLetterTopic,omitempty" yaml:"deadLetterTopic,omitempty"`
+	Rules           []DaprSubscriptionRule `json:"rules,omitempty"           yaml:"rules,omitempty"`
+}
+
+// DaprSubscriptionRule routes the events of a subscription matching Match to Path.
+type DaprSubscriptionRule struct {
+	Match string `json:"match,omitempty" yaml:"match,omitempty"`
+	Path  string `json:"path"            yaml:"path"`
+}
+
+// DaprActor is an actor type hosted by the app of a dapr sidecar.
+type DaprActor struct {
+	Type  string `json:"type"  yaml:"type"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// MetadataEntryOutput is a component, subscription or actor of DaprMetadata
+// as a row of a table.
+type MetadataEntryOutput struct {
+	Kind    string `csv:"KIND"`
+	Name    string `csv:"NAME"`
+	Type    string `csv:"TYPE"`
+	Details string `csv:"DETAILS"`
+}
+
+// Entries lists the components, subscriptions and actors of the metadata.
+func (m *DaprMetadata) Entries() []MetadataEntryOutput {
+	list := make([]MetadataEntryOutput, 0, len(m.Components)+len(m.Subscriptions)+len(m.Actors))
+	for _, c := range m.Components {
+		list = append(list, MetadataEntryOutput{
+			Kind:    MetadataComponent,
+			Name:    c.Name,
+			Type:    fmt.Sprintf("%s/%s", c.Type, c.Version),
+			Details: strings.Join(c.Capabilities, ", "),
+		})
+	}
+	for _, s := range m.Subscriptions {
+		routes := make([]string, 0, len(s.Rules)+1)
+		for _, r := range s.Rules {
+			if r.Match != "" {
+				routes = append(routes, fmt.Sprintf("%s -> %s", r.Match, r.Path))
+			} else {
+				routes = append(routes, r.Path)
+			}
+		}
+		if s.DeadLetterTopic != "" {
+			routes = append(routes, "dead letter topic "+s.DeadLetterTopic)
+		}
+		list = append(list, MetadataEntryOutput{
+			Kind:    MetadataSubscription,
+			Name:    s.Topic,
+			Type:    s.PubsubName,
+			Details: strings.Join(routes, ", "),
+		})
+	}
+	for _, a := range m.Actors {
+		list = append(list, MetadataEntryOutput{
+			Kind:    MetadataActor,
+			Name:    a.Type,
+			Details: fmt.Sprintf("%d active", a.Count),
+		})
+	}
+	return list
+}
+
+// PluginMetadata fetches the metadata of the dapr sidecar of the plugin
+// through a port-forward. An empty podName picks the first running pod.
+func PluginMetadata(pluginID, podName string) (*DaprMetadata, error) {
+	pf, err := GetPortforwardForPod(pluginID, podName, WithHTTPPort, WithAppPod, WithProgress)
+	if err != nil {
+		return nil, err
+	}
+	if err = pf.Init(); err != nil {
+		pf.Stop()
+		return nil, fmt.Errorf("error forwarding to the dapr HTTP port %d of %s: %w", pf.RemotePort, pluginID, err)
+	}
+	defer pf.Stop()
+
+	endpoint := pf.App.MetadataURL(pf.LocalPort, pf.urlOptions("http"))
+	print.Verbosef(print.VerbosityEndpoints, "Fetching %s", endpoint)
+	httpc := &http.Client{Timeout: metadataTimeout}
+	r, err := httpc.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the metadata of %s: %w", pluginID, err)
+	}
+	defer r.Body.Close()
+	body, err := readResponse(r)
+	if err != nil {
+		return nil, err
+	}
+	m := &DaprMetadata{}
+	if err = json.Unmarshal([]byte(body), m); err != nil {
+		return nil, fmt.Errorf("error unmarshal the metadata of %s: %w", pluginID, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+// DefaultMetricsPort is the port the dapr sidecar serves its Prometheus
+// metrics on when it is not started with --metrics-port.
+const DefaultMetricsPort = 9090
+
+const metricsTimeout = 30 * time.Second
+
+// metricsPort returns the port the dapr sidecar of the app serves its metrics on.
+func metricsPort(app *AppPod) int {
+	for _, c := range app.pod.Spec.Containers {
+		if !isDaprSidecar(c) {
+			continue
+		}
+		for i, arg := range c.Args {
+			if arg == "--metrics-port" && i+1 < len(c.Args) {
+				if port, err := strconv.Atoi(c.Args[i+1]); err == nil {
+					return port
+				}
+			}
+		}
+	}
+	return DefaultMetricsPort
+}
+
+// WithMetricsPort forwards to the metrics port of the dapr sidecar, or to
+// port when it is not zero.
+func WithMetricsPort(port int) PortForwardConfigureOption {
+	return func(pf *PortForward, app *AppPod) error {
+		if port == 0 {
+			port = metricsPort(app)
+		}
+		pf.RemotePort = port
+		return nil
+	}
+}
+
+// PluginMetrics fetches the Prometheus metrics served by the dapr sidecar of
+// the plugin through a port-forward to its metrics port, or to port when it
+// is not zero. An empty podName picks the first running pod.
+func PluginMetrics(pluginID, podName string, port int) (string, error) {
+	pf, err := GetPortforwardForPod(pluginID, podName, WithMetricsPort(port), WithAppPod, WithProgress)
+	if err != nil {
+		return "", err
+	}
+	if err = pf.Init(); err != nil {
+		pf.Stop()
+		return "", fmt.Errorf("error forwarding to the metrics port %d of %s: %w", pf.RemotePort, pluginID, err)
+	}
+	defer pf.Stop()
+
+	endpoint := pf.App.MetricsURL(pf.LocalPort, pf.urlOptions("http"))
+	print.Verbosef(print.VerbosityEndpoints, "Fetching %s", endpoint)
+	httpc := &http.Client{Timeout: metricsTimeout}
+	r, err := httpc.Get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("error fetching the metrics of %s, check the sidecar has metrics enabled: %w", pluginID, err)
+	}
+	defer r.Body.Close()
+	return readResponse(r)
+}
+
+// FilterMetrics keeps the samples of the metrics whose name matches re, with
+// their HELP and TYPE comments, from metrics in the Prometheus text format.
+func FilterMetrics(metrics string, re *regexp.Regexp) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(metrics, "\n") {
+		if name := metricName(line); name != "" && re.MatchString(name) {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// metricName returns the name of the metric a line of the Prometheus text
+// format is about, or "" for blank lines and other comments.
+func metricName(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && (fields[1] == "HELP" || fields[1] == "TYPE") {
+			return fields[2]
+		}
+		return ""
+	}
+	if i := strings.IndexAny(line, "{ \t"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
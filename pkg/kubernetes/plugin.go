@@ -12,7 +12,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/dapr/cli/pkg/kubernetes"
 	"github.com/tkeel-io/cli/pkg/client/redis"
 	terrors "github.com/tkeel-io/kit/errors"
 	tenantApi "github.com/tkeel-io/tkeel/api/tenant/v1"
@@ -131,7 +130,7 @@ func List() ([]StatusOutput, error) {
 		if plugin, ok := pluginsMap[appID]; ok {
 			pluginStatus = plugin.Status.String()
 		}
-		if appID == "keel" || appID == "rudder" || appID == "core" {
+		if isControlPlaneApp(appID) {
 			continue
 		}
 		statuses = append(statuses, StatusOutput{
@@ -490,7 +489,7 @@ func CleanInvalidTenants(pluginID string, tenants []string, namespace string) er
 	}
 
 	ctx := context.Background()
-	rdb := redis.NewClient("127.0.0.1", pf.LocalPort, password, 0)
+	rdb := redis.NewClient(pf.localHost(), pf.LocalPort, password, 0)
 	res := rdb.HGet(ctx, fmt.Sprintf("rudder||p_%s", pluginID), "data")
 	if res.Err() != nil {
 		return res.Err()
@@ -513,7 +512,7 @@ func CleanInvalidTenants(pluginID string, tenants []string, namespace string) er
 }
 
 func GetRedisPassword(namespace string) (string, error) {
-	_, client, err := kubernetes.GetKubeConfigClient()
+	_, client, err := GetKubeConfigClient()
 	if err != nil {
 		return "", fmt.Errorf("get kube config error: %w", err)
 	}
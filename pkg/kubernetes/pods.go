@@ -3,15 +3,23 @@ package kubernetes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/dapr/cli/pkg/age"
+	"github.com/tkeel-io/cli/pkg/print"
 	core_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/watch"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -38,22 +46,381 @@ type (
 	DaprAppList []*AppPod
 )
 
+// versionLabel is the pod label carrying the version of the chart that deployed it.
+const versionLabel = "app.kubernetes.io/version"
+
+// PluginPodOutput describes a single pod of a plugin.
+type PluginPodOutput struct {
+	ID        string `csv:"ID"        json:"id"        yaml:"id"`
+	PodName   string `csv:"POD NAME"  json:"pod_name"  yaml:"pod_name"`
+	Namespace string `csv:"NAMESPACE" json:"namespace" yaml:"namespace"`
+	Status    string `csv:"STATUS"    json:"status"    yaml:"status"`
+	Version   string `csv:"VERSION"   json:"version"   yaml:"version"`
+	HTTPPort  int    `csv:"HTTP PORT" json:"http_port" yaml:"http_port"`
+	AppPort   int    `csv:"APP PORT"  json:"app_port"  yaml:"app_port"`
+	Age       string `csv:"AGE"       json:"age"       yaml:"age"`
+}
+
+var (
+	// ErrAppNotFound is returned when no pod of an app is found.
+	ErrAppNotFound = errors.New("not found")
+	// ErrPodNotFound is returned when the pod an app is pinned to is not one of its pods.
+	ErrPodNotFound = errors.New("pod not found")
+	// ErrPodNotRunning is returned when the pod picked to reach an app is not
+	// running, or when an app has no running pod at all.
+	ErrPodNotRunning = errors.New("pod not running")
+	// ErrAmbiguousApp is returned when pods of an app are found in several
+	// namespaces while looking them up in all of them.
+	ErrAmbiguousApp = errors.New("found in several namespaces")
+)
+
 func GetAppPod(client k8s.Interface, appID string) (*AppPod, error) {
+	list, err := GetAppPods(client, appID)
+	if err != nil {
+		return nil, err
+	}
+	app := list[0]
+	return app, nil
+}
+
+// GetAppPods returns all the pods of the app, the running ones first.
+// Looking up all namespaces, the pods must be in a single one of them.
+func GetAppPods(client k8s.Interface, appID string) (DaprAppList, error) {
+	list, err := appPods(client, appID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSingleNamespace(appID, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// appPods returns all the pods of the app, the running ones first.
+func appPods(client k8s.Interface, appID string) (DaprAppList, error) {
 	list, err := ListAppInfos(client, appID)
 	if err != nil {
 		return nil, err
 	}
 	if len(list) == 0 {
-		return nil, fmt.Errorf("%s not found", appID)
+		return nil, fmt.Errorf("%s %w", appID, ErrAppNotFound)
 	}
-	app := list[0]
-	return app, nil
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].pod.Status.Phase == core_v1.PodRunning && list[j].pod.Status.Phase != core_v1.PodRunning
+	})
+	return list, nil
+}
+
+// checkSingleNamespace returns an ErrAmbiguousApp error listing the pods of
+// the app when they are in several namespaces and Namespace is AllNamespaces.
+func checkSingleNamespace(appID string, list DaprAppList) error {
+	if Namespace != AllNamespaces {
+		return nil
+	}
+	candidates := make([]string, 0, len(list))
+	namespaces := map[string]bool{}
+	for _, app := range list {
+		namespaces[app.Namespace] = true
+		candidates = append(candidates, app.Namespace+"/"+app.PodName)
+	}
+	if len(namespaces) < 2 {
+		return nil
+	}
+	sort.Strings(candidates)
+	return &UsageError{fmt.Errorf("%s %w, pick one with -n: %s", appID, ErrAmbiguousApp, strings.Join(candidates, ", "))}
 }
 
-// ListAppInfos ListPluginPods outputs plugins list.
+// SelectAppPod returns the pod of the app named podName. With an empty
+// podName the first running pod is picked, and a note names the chosen pod
+// when the app has several replicas so the choice can be reproduced.
+func SelectAppPod(client k8s.Interface, appID, podName string) (*AppPod, error) {
+	if podName == "" {
+		list, err := GetAppPods(client, appID)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) > 1 {
+			print.Verbosef(print.VerbosityEndpoints, "Using pod %s/%s of %s, one of its %d pods (pin one with --pod)", list[0].Namespace, list[0].PodName, appID, len(list))
+		} else {
+			print.Verbosef(print.VerbosityEndpoints, "Using pod %s/%s of %s", list[0].Namespace, list[0].PodName, appID)
+		}
+		return list[0], nil
+	}
+	// a pinned pod settles which namespace the app is picked in.
+	list, err := appPods(client, appID)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range list {
+		if app.PodName == podName {
+			print.Verbosef(print.VerbosityEndpoints, "Using pod %s/%s of %s", app.Namespace, app.PodName, appID)
+			return app, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is not a pod of %s", ErrPodNotFound, podName, appID)
+}
+
+// checkPodRunning returns ErrPodNotRunning unless the pod of app is running.
+func checkPodRunning(app *AppPod) error {
+	if phase := app.pod.Status.Phase; phase != core_v1.PodRunning {
+		return fmt.Errorf("%w: %s/%s of %s is %s", ErrPodNotRunning, app.Namespace, app.PodName, app.AppID, phase)
+	}
+	return nil
+}
+
+// controlPlaneApps are the dapr apps making up the tKeel control plane,
+// they are not plugins.
+var controlPlaneApps = []string{_pluginKeel, _pluginRudder, pluginCore}
+
+func isControlPlaneApp(appID string) bool {
+	return contains(controlPlaneApps, appID)
+}
+
+// ListPluginPods lists every pod of the installed plugins with its phase and ports.
+func ListPluginPods() ([]PluginPodOutput, error) {
+	return ListPluginPodsSelected(PodSelector{})
+}
+
+// ListPluginPodsSelected is ListPluginPods keeping the pods matching sel.
+func ListPluginPodsSelected(sel PodSelector) ([]PluginPodOutput, error) {
+	if err := sel.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := ListAppInfosSelected(client, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]PluginPodOutput, 0, len(apps))
+	for _, app := range apps {
+		if isControlPlaneApp(app.AppID) {
+			continue
+		}
+		list = append(list, PluginPodOutput{
+			ID:        app.AppID,
+			PodName:   app.PodName,
+			Namespace: app.Namespace,
+			Status:    string(app.pod.Status.Phase),
+			Version:   app.pod.Labels[versionLabel],
+			HTTPPort:  app.HTTPPort,
+			AppPort:   app.AppPort,
+			Age:       age.GetAge(app.pod.CreationTimestamp.Time),
+		})
+	}
+	return list, nil
+}
+
+// PluginPodStatusOutput describes the health of the running pod of a plugin.
+type PluginPodStatusOutput struct {
+	ID        string `csv:"ID"        json:"id"        yaml:"id"`
+	PodName   string `csv:"POD NAME"  json:"pod_name"  yaml:"pod_name"`
+	Namespace string `csv:"NAMESPACE" json:"namespace" yaml:"namespace"`
+	Phase     string `csv:"PHASE"     json:"phase"     yaml:"phase"`
+	Ready     string `csv:"READY"     json:"ready"     yaml:"ready"`
+	Restarts  int32  `csv:"RESTARTS"  json:"restarts"  yaml:"restarts"`
+	Age       string `csv:"AGE"       json:"age"       yaml:"age"`
+	HTTPPort  int    `csv:"HTTP PORT" json:"http_port" yaml:"http_port"`
+	AppPort   int    `csv:"APP PORT"  json:"app_port"  yaml:"app_port"`
+	GRPCPort  int    `csv:"GRPC PORT" json:"grpc_port" yaml:"grpc_port"`
+}
+
+// PluginPodStatus reports the health of the first running pod of the plugin,
+// or ErrPodNotRunning when none of its pods is running.
+func PluginPodStatus(pluginID string) (*PluginPodStatusOutput, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := ListAppInfos(client, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		if app.pod.Status.Phase == core_v1.PodRunning {
+			return podStatusOutput(app), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s has no running pod", ErrPodNotRunning, pluginID)
+}
+
+// WatchPluginPodStatus calls fn with the status of the plugin's pods each time
+// one of them changes, until a pod is running with all its containers ready or
+// ctx is done.
+func WatchPluginPodStatus(ctx context.Context, pluginID string, fn func(*PluginPodStatusOutput)) error {
+	client, err := Client()
+	if err != nil {
+		return err
+	}
+
+	namespace, guessed := lookupNamespace()
+	podList, err := client.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("err get pods list:%w", err)
+	}
+	found := false
+	for i := range podList.Items {
+		app := getAppInfoFromPod((*DaprPod)(&podList.Items[i]))
+		if app == nil || app.AppID != pluginID {
+			continue
+		}
+		found = true
+		fn(podStatusOutput(app))
+		if podReady(app.pod) {
+			return nil
+		}
+	}
+	if !found && guessed {
+		// the plugin may not be in the context namespace, watch them all.
+		namespace = v1.NamespaceAll
+	}
+
+	resourceVersion := podList.ResourceVersion
+	for {
+		w, err := client.CoreV1().Pods(namespace).Watch(ctx, v1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("err watch pods:%w", err)
+		}
+		ready, rv, err := watchAppPods(ctx, w, pluginID, fn)
+		w.Stop()
+		if apierrors.IsGone(err) || apierrors.IsResourceExpired(err) {
+			// the version we watched from is too old, start over from now.
+			resourceVersion = ""
+			continue
+		}
+		if err != nil || ready {
+			return err
+		}
+		// the server closed the watch, resume from the last seen version.
+		resourceVersion = rv
+	}
+}
+
+func watchAppPods(ctx context.Context, w watch.Interface, appID string, fn func(*PluginPodStatusOutput)) (ready bool, resourceVersion string, err error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, resourceVersion, ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return false, resourceVersion, nil
+			}
+			if event.Type == watch.Error {
+				return false, resourceVersion, apierrors.FromObject(event.Object)
+			}
+			pod, ok := event.Object.(*core_v1.Pod)
+			if !ok {
+				continue
+			}
+			resourceVersion = pod.ResourceVersion
+			if event.Type == watch.Deleted {
+				continue
+			}
+			app := getAppInfoFromPod((*DaprPod)(pod))
+			if app == nil || app.AppID != appID {
+				continue
+			}
+			fn(podStatusOutput(app))
+			if podReady(app.pod) {
+				return true, resourceVersion, nil
+			}
+		}
+	}
+}
+
+func podStatusOutput(app *AppPod) *PluginPodStatusOutput {
+	ready, restarts := 0, int32(0)
+	for _, c := range app.pod.Status.ContainerStatuses {
+		if c.Ready {
+			ready++
+		}
+		restarts += c.RestartCount
+	}
+	return &PluginPodStatusOutput{
+		ID:        app.AppID,
+		PodName:   app.PodName,
+		Namespace: app.Namespace,
+		Phase:     string(app.pod.Status.Phase),
+		Ready:     fmt.Sprintf("%d/%d", ready, len(app.pod.Spec.Containers)),
+		Restarts:  restarts,
+		Age:       age.GetAge(app.pod.CreationTimestamp.Time),
+		HTTPPort:  app.HTTPPort,
+		AppPort:   app.AppPort,
+		GRPCPort:  app.GRPCPort,
+	}
+}
+
+// podReady reports whether the pod is running with all its containers ready.
+func podReady(p *DaprPod) bool {
+	if p.Status.Phase != core_v1.PodRunning || p.DeletionTimestamp != nil {
+		return false
+	}
+	for _, c := range p.Status.ContainerStatuses {
+		if !c.Ready {
+			return false
+		}
+	}
+	return len(p.Status.ContainerStatuses) > 0
+}
+
+// ListAppInfos outputs the dapr apps in the cluster, filtered by appIDs if given.
 func ListAppInfos(client k8s.Interface, appIDs ...string) (DaprAppList, error) {
-	opts := v1.ListOptions{}
-	podList, err := client.CoreV1().Pods(v1.NamespaceAll).List(context.TODO(), opts)
+	return ListAppInfosSelected(client, PodSelector{}, appIDs...)
+}
+
+// ListAppInfosSelected is ListAppInfos keeping the pods matching sel.
+func ListAppInfosSelected(client k8s.Interface, sel PodSelector, appIDs ...string) (DaprAppList, error) {
+	namespace, guessed := lookupNamespace()
+	l, err := listAppInfos(client, namespace, sel, appIDs...)
+	if err == nil && len(l) == 0 && namespace != v1.NamespaceAll && (guessed || controlPlaneOnly(appIDs)) {
+		// nothing in the context namespace, tKeel may live elsewhere. -n
+		// picks the namespace of the plugins, the control plane may be
+		// installed in another one.
+		return listAppInfos(client, v1.NamespaceAll, sel, appIDs...)
+	}
+	return l, err
+}
+
+// controlPlaneOnly reports whether appIDs only name control plane apps.
+func controlPlaneOnly(appIDs []string) bool {
+	for _, appID := range appIDs {
+		if !isControlPlaneApp(appID) {
+			return false
+		}
+	}
+	return len(appIDs) > 0
+}
+
+// PodSelector filters pods by labels and fields, in the syntax of the
+// kubectl --selector and --field-selector flags. Empty selectors match all.
+type PodSelector struct {
+	Label string
+	Field string
+}
+
+// Validate reports a selector that does not parse as a UsageError.
+func (s PodSelector) Validate() error {
+	if _, err := labels.Parse(s.Label); err != nil {
+		return &UsageError{fmt.Errorf("invalid label selector %q: %w", s.Label, err)}
+	}
+	if _, err := fields.ParseSelector(s.Field); err != nil {
+		return &UsageError{fmt.Errorf("invalid field selector %q: %w", s.Field, err)}
+	}
+	return nil
+}
+
+func listAppInfos(client k8s.Interface, namespace string, sel PodSelector, appIDs ...string) (DaprAppList, error) {
+	opts := v1.ListOptions{LabelSelector: sel.Label, FieldSelector: sel.Field}
+	podList, err := client.CoreV1().Pods(namespace).List(context.TODO(), opts)
 	if err != nil {
 		return nil, fmt.Errorf("err get pods list:%w", err)
 	}
@@ -79,7 +446,7 @@ func ListAppInfos(client k8s.Interface, appIDs ...string) (DaprAppList, error) {
 	for _, p := range podList.Items {
 		p := DaprPod(p)
 		for _, c := range p.Spec.Containers {
-			if c.Name == "daprd" {
+			if isDaprSidecar(c) {
 				app := getAppInfoFromPod(&p)
 				if fn(app) {
 					l = append(l, app)
@@ -93,7 +460,7 @@ func ListAppInfos(client k8s.Interface, appIDs ...string) (DaprAppList, error) {
 
 func getAppInfoFromPod(p *DaprPod) (a *AppPod) {
 	for _, c := range p.Spec.Containers {
-		if c.Name == "daprd" {
+		if isDaprSidecar(c) {
 			a = &AppPod{
 				AppInfo: AppInfo{
 					PodName:   p.Name,
@@ -130,6 +497,8 @@ func getAppInfoFromPod(p *DaprPod) (a *AppPod) {
 	return a
 }
 
+// Request points r at method of the app through the pod proxy of the API
+// server. data is sent as the body whatever the verb, including DELETE.
 func (a *AppInfo) Request(r *rest.Request, method string, data []byte) (*rest.Request, error) {
 	r = r.Namespace(a.Namespace).
 		Resource("pods").
@@ -140,7 +509,7 @@ func (a *AppInfo) Request(r *rest.Request, method string, data []byte) (*rest.Re
 		r = r.Body(data)
 	}
 
-	u, err := url.Parse(method)
+	u, err := url.Parse(escapeMethod(method))
 	if err != nil {
 		return nil, fmt.Errorf("error parse method %s: %w", method, err)
 	}
@@ -166,7 +535,7 @@ func ListPlugins(client k8s.Interface) ([]*Plugin, error) {
 	}
 
 	result := res.Do(context.TODO())
-	if result.Error() != nil {
+	if err = result.Error(); err != nil {
 		return nil, fmt.Errorf("k8s query resutl err: %w", err)
 	}
 	rawbody, err := result.Raw()
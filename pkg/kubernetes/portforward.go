@@ -17,34 +17,76 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
-
-	"github.com/dapr/cli/pkg/kubernetes"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tkeel-io/cli/pkg/print"
+	"k8s.io/apimachinery/pkg/util/httpstream"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
 )
 
+// DefaultAddress is the local address port-forwards listen on unless told otherwise.
+const DefaultAddress = "127.0.0.1"
+
+// ErrLocalPortInUse is returned when a fixed local port of a port-forward is taken.
+var ErrLocalPortInUse = errors.New("in use")
+
+// PortPair is a local to remote port mapping of a port-forward.
+// A zero Local port picks a random one.
+type PortPair struct {
+	Local  int
+	Remote int
+}
+
 // PortForward provides a port-forward connection in a kubernetes cluster.
+// When Ports is empty the single LocalPort/RemotePort pair is forwarded.
 type PortForward struct {
 	Config     *rest.Config
 	Method     string
 	URL        *url.URL
-	Host       string
+	Host       string // comma separated local addresses to listen on.
 	LocalPort  int
 	RemotePort int
 	EmitLogs   bool
+	Ports      []PortPair
 	App        *AppPod
 	StopCh     chan struct{}
 	ReadyCh    chan struct{}
+
+	stopOnce      sync.Once
+	autoReconnect bool
+	appID         string
+	pinnedPod     string
+	progress      bool
+	// dialer upgrades the connection to the pod, a spdy dialer of Config
+	// when nil.
+	dialer httpstream.Dialer
+
+	// mu guards the fields a reconnect points at the new pod: URL, ReadyCh,
+	// App and podName.
+	mu      sync.Mutex
+	podName string
 }
 
+// reconnectMaxAttempts bounds the attempts to reconnect a dropped forward,
+// about five minutes with the retry backoff.
+const reconnectMaxAttempts = 30
+
 // NewPortForward returns an instance of PortForward struct that can be used
 // for establishing port-forwarding connection to a pod in kubernetes cluster,
 // specified by namespace and deployName.
@@ -59,75 +101,358 @@ func NewPortForward(
 		return nil, fmt.Errorf("error get k8s client: %w", err)
 	}
 
-	req := client.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Namespace(namespace).
-		Name(podName).
-		SubResource("portforward")
-
 	return &PortForward{
 		Config:     config,
 		Method:     "POST",
-		URL:        req.URL(),
+		URL:        podPortForwardURL(client, namespace, podName),
 		Host:       host,
 		LocalPort:  localPort,
 		RemotePort: remotePort,
 		EmitLogs:   emitLogs,
 		StopCh:     make(chan struct{}, 1),
 		ReadyCh:    make(chan struct{}),
+		podName:    podName,
 	}, nil
 }
 
+func podPortForwardURL(client k8s.Interface, namespace, podName string) *url.URL {
+	return client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward").
+		URL()
+}
+
 // Init creates and runs a port-forward connection.
 // This function blocks until connection is established.
 // Note: Caller should call Stop() to finish the connection.
 func (pf *PortForward) Init() error {
-	transport, upgrader, err := spdy.RoundTripperFor(pf.Config)
+	stopProgress := func() {}
+	if pf.progress {
+		pf.mu.Lock()
+		podName := pf.podName
+		pf.mu.Unlock()
+		stopProgress = print.Progress(os.Stderr, "Establishing port-forward to %s…", podName)
+	}
+	done, err := pf.forward()
+	stopProgress()
 	if err != nil {
-		return fmt.Errorf("error creat spdy round tripper: %w", err)
+		return err
+	}
+	if pf.autoReconnect {
+		go pf.reconnectOnDrop(done)
+		return nil
 	}
+	// without reconnecting a dropped forward is over, let GetStop tell.
+	go func() {
+		<-done
+		pf.Stop()
+	}()
+	return nil
+}
 
+// forward starts forwarding the ports and waits until it is ready. The
+// returned channel receives once the forward ends, whatever the reason.
+func (pf *PortForward) forward() (<-chan error, error) {
+	pairs := pf.portPairs()
+	done, err := pf.forwardPairs(pairs)
+	if err != nil && isListenError(err) && allocatesLocalPort(pairs) {
+		// the probed port was taken before the forward bound it, e.g. by a
+		// concurrent forward, probe a new one.
+		print.Verbosef(print.VerbosityPortForward, "Local port taken before the forward started, retrying: %s", err)
+		done, err = pf.forwardPairs(pairs)
+	}
+	if err != nil && isListenError(err) {
+		return nil, fmt.Errorf("error listen on local port on %s: %w", pf.Host, err)
+	}
+	return done, err
+}
+
+// forwardPairs forwards the port pairs, picking a free local port for the
+// pairs without one.
+func (pf *PortForward) forwardPairs(pairs []PortPair) (<-chan error, error) {
 	out := ioutil.Discard
 	errOut := ioutil.Discard
 	if pf.EmitLogs {
-		out = os.Stdout
+		// keep the port-forward logs apart from the command output.
+		out = os.Stderr
 		errOut = os.Stderr
 	}
 
-	ports := []string{fmt.Sprintf("%d:%d", pf.LocalPort, pf.RemotePort)}
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, pf.Method, pf.URL)
+	addresses := pf.addresses()
+	for _, pair := range pairs {
+		if err := checkLocalPort(addresses, pair.Local); err != nil {
+			return nil, err
+		}
+	}
+	pairs, err := allocateLocalPorts(addresses, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		ports = append(ports, fmt.Sprintf("%d:%d", pair.Local, pair.Remote))
+	}
+	pf.mu.Lock()
+	target, readyCh := pf.URL, pf.ReadyCh
+	pf.mu.Unlock()
+	dialer := pf.dialer
+	if dialer == nil {
+		transport, upgrader, err := spdyRoundTripper(pf.Config)
+		if err != nil {
+			return nil, err
+		}
+		dialer = spdy.NewDialer(upgrader, &http.Client{Transport: transport}, pf.Method, target)
+	}
 
-	fw, err := portforward.NewOnAddresses(dialer, []string{pf.Host}, ports, pf.StopCh, pf.ReadyCh, out, errOut)
+	fw, err := portforward.NewOnAddresses(dialer, addresses, ports, pf.StopCh, readyCh, out, errOut)
 	if err != nil {
-		return fmt.Errorf("error creat portforward: %w", err)
+		return nil, fmt.Errorf("error creat portforward: %w", err)
 	}
 
-	failure := make(chan error)
+	// ForwardPorts returns nil when the connection to the pod is lost, so
+	// any return counts as the end of the forward.
+	done := make(chan error, 1)
 	go func() {
-		if err := fw.ForwardPorts(); err != nil {
-			failure <- err
-		}
+		done <- fw.ForwardPorts()
 	}()
 
 	select {
 	// if `fw.ForwardPorts()` succeeds, block until terminated
-	case <-pf.ReadyCh:
+	case <-readyCh:
 		ports, err := fw.GetPorts()
+		if err == nil && len(ports) > 0 {
+			pf.Ports = make([]PortPair, 0, len(ports))
+			for _, port := range ports {
+				pf.Ports = append(pf.Ports, PortPair{Local: int(port.Local), Remote: int(port.Remote)})
+			}
+			pf.LocalPort = pf.Ports[0].Local
+			pf.RemotePort = pf.Ports[0].Remote
+		}
+	// if failure, causing a receive `<-done` and returns the error
+	case err := <-done:
 		if err == nil {
-			pf.LocalPort = int(ports[0].Local)
-			pf.RemotePort = int(ports[0].Remote)
+			err = errors.New("port forward ended before it was ready")
+		}
+		return nil, err
+	// if stopped before being ready, e.g. interrupted by the user
+	case <-pf.StopCh:
+		return nil, errors.New("port forward stopped before it was ready")
+	}
+
+	return done, nil
+}
+
+// spdyRoundTripper returns the round tripper and upgrader the streaming pod
+// subresources, portforward and exec, are dialed with.
+func spdyRoundTripper(config *rest.Config) (http.RoundTripper, spdy.Upgrader, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creat spdy round tripper: %w", err)
+	}
+	return transport, upgrader, nil
+}
+
+// reconnectOnDrop re-establishes the forward each time it drops, e.g. when
+// the pod restarts, until the port-forward is stopped. The pod is looked up
+// again as a restarted pod usually comes back under a new name, unless the
+// forward is pinned to a pod, and the local ports are kept so clients can
+// keep using the same address. It stops the forward after
+// reconnectMaxAttempts failed attempts.
+func (pf *PortForward) reconnectOnDrop(done <-chan error) {
+	for {
+		select {
+		case <-pf.StopCh:
+			return
+		case err := <-done:
+			select {
+			case <-pf.StopCh:
+				return
+			default:
+			}
+			if err == nil {
+				err = errors.New("lost connection to pod")
+			}
+			print.WarningStatusEvent(os.Stderr, "Port-forward to %s dropped: %s, reconnecting", pf.appID, err)
+		}
+
+		var err error
+		for attempt := 0; attempt < reconnectMaxAttempts; attempt++ {
+			select {
+			case <-pf.StopCh:
+				return
+			case <-time.After(retryBackoff(attempt)):
+			}
+
+			if err = pf.resolvePod(); err == nil {
+				done, err = pf.forward()
+			}
+			if err == nil {
+				print.InfoStatusEvent(os.Stderr, "Port-forward to %s reconnected", pf.appID)
+				break
+			}
+			print.Verbosef(print.VerbosityEndpoints, "Reconnect attempt %d to %s failed: %s", attempt+1, pf.appID, err)
+		}
+		if err != nil {
+			print.FailureStatusEvent(os.Stderr, "Port-forward to %s not reconnected after %d attempts: %s", pf.appID, reconnectMaxAttempts, err)
+			pf.Stop()
+			return
 		}
-	// if failure, causing a receive `<-failure` and returns the error
-	case err := <-failure:
+	}
+}
+
+// resolvePod points the forward at the current running pod of the app, or
+// at the pinned pod once it runs again.
+func (pf *PortForward) resolvePod() error {
+	client, err := k8s.NewForConfig(pf.Config)
+	if err != nil {
+		return fmt.Errorf("error get k8s client: %w", err)
+	}
+	app, err := SelectAppPod(client, pf.appID, pf.pinnedPod)
+	if err != nil {
+		return err
+	}
+	if err = checkPodRunning(app); err != nil {
 		return err
 	}
 
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	pf.URL = podPortForwardURL(client, app.Namespace, app.PodName)
+	pf.podName = app.PodName
+	pf.ReadyCh = make(chan struct{})
+	if pf.App != nil {
+		pf.App = app
+	}
+	return nil
+}
+
+// AppPod returns the pod of the app the forward currently points at, which
+// changes when it reconnects to a restarted pod.
+func (pf *PortForward) AppPod() *AppPod {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return pf.App
+}
+
+// portPairs returns the port mappings to forward.
+func (pf *PortForward) portPairs() []PortPair {
+	if len(pf.Ports) > 0 {
+		return pf.Ports
+	}
+	return []PortPair{{Local: pf.LocalPort, Remote: pf.RemotePort}}
+}
+
+// allocateLocalPorts returns the pairs with a free local port picked for
+// those without one. The ports are probed by listening on port 0 of the first
+// address, rather than leaving the pick to the forwarder, which picks per
+// address. The probes are held until all are picked so they are distinct, and
+// closed before returning, so another process may take one before the
+// forwarder binds it; forward then allocates the ports again.
+func allocateLocalPorts(addresses []string, pairs []PortPair) ([]PortPair, error) {
+	if !allocatesLocalPort(pairs) {
+		return pairs, nil
+	}
+	allocated := make([]PortPair, len(pairs))
+	copy(allocated, pairs)
+
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	for i := range allocated {
+		if allocated[i].Local != 0 {
+			continue
+		}
+		port, l, err := probeLocalPort(addresses)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, l)
+		allocated[i].Local = port
+	}
+	return allocated, nil
+}
+
+// probeLocalPort returns a port free on all the addresses, with the
+// listener holding it on the first one, retrying once when it is taken on
+// another address.
+func probeLocalPort(addresses []string) (int, net.Listener, error) {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var l net.Listener
+		l, err = net.Listen("tcp", net.JoinHostPort(addresses[0], "0"))
+		if err != nil {
+			return 0, nil, fmt.Errorf("error allocate local port on %s: %w", addresses[0], err)
+		}
+		port := l.Addr().(*net.TCPAddr).Port
+		if err = checkLocalPort(addresses[1:], port); err == nil {
+			return port, l, nil
+		}
+		l.Close()
+	}
+	return 0, nil, fmt.Errorf("no local port free on all of %s: %w", strings.Join(addresses, ","), err)
+}
+
+// allocatesLocalPort reports whether a local port is picked for any of the pairs.
+func allocatesLocalPort(pairs []PortPair) bool {
+	for _, pair := range pairs {
+		if pair.Local == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isListenError reports whether the forward failed to listen on a local port.
+func isListenError(err error) bool {
+	return errors.Is(err, ErrLocalPortInUse) || strings.Contains(err.Error(), "unable to listen on")
+}
+
+// checkLocalPort makes sure a fixed local port is free on every address, so a
+// collision is reported explicitly rather than as a raw bind failure.
+func checkLocalPort(addresses []string, port int) error {
+	if port == 0 {
+		return nil
+	}
+	for _, addr := range addresses {
+		l, err := net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+		if err != nil {
+			if errors.Is(err, syscall.EADDRINUSE) {
+				return fmt.Errorf("local port %d already %w", port, ErrLocalPortInUse)
+			}
+			return fmt.Errorf("error listen on local port %d: %w", port, err)
+		}
+		l.Close()
+	}
 	return nil
 }
 
 // Stop terminates port-forwarding connection.
+// It is safe to call Stop more than once.
 func (pf *PortForward) Stop() {
-	close(pf.StopCh)
+	pf.stopOnce.Do(func() {
+		close(pf.StopCh)
+	})
+}
+
+// stopOnInterrupt stops the port-forwarding connection when the process is
+// interrupted, so callers get to run their own teardown instead of the
+// process exiting underneath them.
+func (pf *PortForward) stopOnInterrupt() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer cancel()
+		select {
+		case <-ctx.Done():
+			pf.Stop()
+		case <-pf.StopCh:
+		}
+	}()
 }
 
 // GetStop returns StopCh for a PortForward instance.
@@ -137,75 +462,69 @@ func (pf *PortForward) GetStop() <-chan struct{} {
 }
 
 func GetPortforward(appName string, options ...PortForwardConfigureOption) (*PortForward, error) {
-	config, client, err := kubernetes.GetKubeConfigClient()
+	return GetPortforwardForPod(appName, "", options...)
+}
+
+// GetPortforwardForPod is GetPortforward pinned to the named pod of the app,
+// an empty podName picks the first running one.
+func GetPortforwardForPod(appName, podName string, options ...PortForwardConfigureOption) (*PortForward, error) {
+	config, client, err := GetKubeConfigClient()
 	if err != nil {
 		return nil, fmt.Errorf("get kube config error: %w", err)
 	}
 
-	// manage termination of port forwarding connection on interrupt
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt)
-	defer signal.Stop(signals)
-
-	app, err := GetAppPod(client, appName)
+	app, err := SelectAppPod(client, appName, podName)
 	if err != nil {
 		return nil, err
 	}
+	if err = checkPodRunning(app); err != nil {
+		return nil, err
+	}
 
 	portForward, err := NewPortForward(
 		config,
 		app.Namespace, app.PodName,
-		"127.0.0.1",
+		DefaultAddress,
 		0,
 		app.HTTPPort,
-		false,
+		print.Verbose(print.VerbosityPortForward),
 	)
 
-	go func() {
-		<-signals
-		os.Exit(0)
-	}()
-
 	if err != nil {
 		return nil, fmt.Errorf("new portforward failed: %w", err)
 	}
+	portForward.pinnedPod = podName
 	for i := 0; i < len(options); i++ {
 		if err := options[i](portForward, app); err != nil {
 			return nil, fmt.Errorf("set portforward options failed: %w", err)
 		}
 	}
+	// manage termination of port forwarding connection on interrupt
+	portForward.stopOnInterrupt()
 	return portForward, nil
 }
 
 func GetPodPortForward(name, namespace string, port int) (*PortForward, error) {
-	config, _, err := kubernetes.GetKubeConfigClient()
+	config, _, err := GetKubeConfigClient()
 	if err != nil {
 		return nil, fmt.Errorf("get kube config error: %w", err)
 	}
 
-	// manage termination of port forwarding connection on interrupt
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt)
-	defer signal.Stop(signals)
-
 	portForward, err := NewPortForward(
 		config,
 		namespace,
 		name,
-		"127.0.0.1",
+		DefaultAddress,
 		0,
 		port,
-		false,
+		print.Verbose(print.VerbosityPortForward),
 	)
 
-	go func() {
-		<-signals
-		os.Exit(0)
-	}()
-
 	if err != nil {
 		return nil, fmt.Errorf("new portforward failed: %w", err)
 	}
+	// manage termination of port forwarding connection on interrupt
+	portForward.stopOnInterrupt()
 	return portForward, nil
 }
 
@@ -225,3 +544,93 @@ func WithAppPod(pf *PortForward, app *AppPod) error {
 	pf.App = app
 	return nil
 }
+
+// WithAutoReconnect re-establishes the port-forward when it drops, looking up
+// the running pod of the app again, or the pod it is pinned to, with a
+// bounded backoff between attempts.
+func WithAutoReconnect(pf *PortForward, app *AppPod) error {
+	pf.autoReconnect = true
+	pf.appID = app.AppID
+	return nil
+}
+
+// WithProgress shows a spinner on stderr while Init establishes the forward,
+// when stderr is a terminal.
+func WithProgress(pf *PortForward, app *AppPod) error {
+	pf.progress = true
+	return nil
+}
+
+// WithPorts forwards all the given port pairs instead of a single one.
+func WithPorts(pairs ...PortPair) PortForwardConfigureOption {
+	return func(pf *PortForward, app *AppPod) error {
+		pf.Ports = pairs
+		return nil
+	}
+}
+
+// WithDaprPorts forwards both the dapr HTTP and gRPC ports of the app.
+func WithDaprPorts(pf *PortForward, app *AppPod) error {
+	pf.Ports = []PortPair{{Remote: app.HTTPPort}, {Remote: app.GRPCPort}}
+	return nil
+}
+
+// WithLocalPort makes the port-forward listen on a fixed local port, zero picks a random one.
+func WithLocalPort(port int) PortForwardConfigureOption {
+	return func(pf *PortForward, app *AppPod) error {
+		pf.LocalPort = port
+		return nil
+	}
+}
+
+// addresses returns the local addresses the port-forward listens on,
+// DefaultAddress when none is set.
+func (pf *PortForward) addresses() []string {
+	var addresses []string
+	for _, addr := range strings.Split(pf.Host, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addresses = append(addresses, addr)
+		}
+	}
+	if len(addresses) == 0 {
+		return []string{DefaultAddress}
+	}
+	return addresses
+}
+
+// localHost returns the host to reach the forwarded port at: the first
+// address it listens on, its loopback when that is all interfaces.
+func (pf *PortForward) localHost() string {
+	addr := pf.addresses()[0]
+	if ip := net.ParseIP(addr); ip != nil && ip.IsUnspecified() {
+		if ip.To4() != nil {
+			return DefaultAddress
+		}
+		return net.IPv6loopback.String()
+	}
+	return addr
+}
+
+// WithAddress makes the port-forward listen on the comma separated addresses.
+func WithAddress(address string) PortForwardConfigureOption {
+	return func(pf *PortForward, app *AppPod) error {
+		pf.Host = address
+		return nil
+	}
+}
+
+// ValidateAddress checks the comma separated addresses are IPs a port-forward
+// can listen on. Listening on all interfaces exposes the forwarded port to
+// other machines, so it is refused unless allowAll is set.
+func ValidateAddress(address string, allowAll bool) error {
+	for _, addr := range strings.Split(address, ",") {
+		ip := net.ParseIP(strings.TrimSpace(addr))
+		if ip == nil {
+			return fmt.Errorf("invalid address %q, expected an IP address", addr)
+		}
+		if ip.IsUnspecified() && !allowAll {
+			return fmt.Errorf("address %s listens on all interfaces, pass --allow-all to confirm", addr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package kubernetes
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+)
+
+func Test_allocateLocalPorts(t *testing.T) {
+	addresses := []string{DefaultAddress}
+	pairs, err := allocateLocalPorts(addresses, []PortPair{{Local: 0, Remote: 3500}, {Local: 4000, Remote: 50001}, {Local: 0, Remote: 8080}})
+	assert.NoError(t, err)
+	assert.NotZero(t, pairs[0].Local)
+	assert.Equal(t, 4000, pairs[1].Local)
+	assert.NotZero(t, pairs[2].Local)
+	assert.NotEqual(t, pairs[0].Local, pairs[2].Local)
+}
+
+// stubConn is a connection to a pod that stays open until closed. It serves
+// no streams, nothing is sent through the forwards of the tests.
+type stubConn struct {
+	httpstream.Connection
+	closeOnce sync.Once
+	closed    chan bool
+}
+
+func (c *stubConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *stubConn) CloseChan() <-chan bool {
+	return c.closed
+}
+
+// stubDialer hands out stubConn connections instead of upgrading a
+// connection to the API server.
+type stubDialer struct{}
+
+func (stubDialer) Dial(protocols ...string) (httpstream.Connection, string, error) {
+	return &stubConn{closed: make(chan bool)}, portforward.PortForwardProtocolV1Name, nil
+}
+
+func Test_forwardConcurrently(t *testing.T) {
+	const forwards = 20
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		ports = map[int]bool{}
+		errs  []error
+	)
+	for i := 0; i < forwards; i++ {
+		pf := &PortForward{
+			Host:    DefaultAddress,
+			Ports:   []PortPair{{Remote: 3500}, {Remote: 50001}},
+			StopCh:  make(chan struct{}, 1),
+			ReadyCh: make(chan struct{}),
+			dialer:  stubDialer{},
+		}
+		// the forwards stay open so every one holds its ports until the end.
+		t.Cleanup(pf.Stop)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pf.forward()
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			for _, pair := range pf.Ports {
+				ports[pair.Local] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, errs)
+	assert.Len(t, ports, forwards*2)
+	for port := range ports {
+		assert.ErrorIs(t, checkLocalPort([]string{DefaultAddress}, port), ErrLocalPortInUse, "port %d not bound by its forward", port)
+	}
+}
+
+func Test_checkLocalPortInUse(t *testing.T) {
+	l, err := net.Listen("tcp", net.JoinHostPort(DefaultAddress, "0"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	err = checkLocalPort([]string{DefaultAddress}, port)
+	assert.True(t, errors.Is(err, ErrLocalPortInUse))
+	assert.True(t, isListenError(err))
+	assert.EqualError(t, err, "local port "+strconv.Itoa(port)+" already in use")
+}
+
+func Test_portForwardLocalHost(t *testing.T) {
+	testCases := []struct {
+		host      string
+		addresses []string
+		localHost string
+		url       string
+	}{
+		{host: "", addresses: []string{"127.0.0.1"}, localHost: "127.0.0.1", url: "http://127.0.0.1:3500/v1.0/healthz"},
+		{host: "127.0.0.1, ::1", addresses: []string{"127.0.0.1", "::1"}, localHost: "127.0.0.1", url: "http://127.0.0.1:3500/v1.0/healthz"},
+		{host: "10.0.0.5", addresses: []string{"10.0.0.5"}, localHost: "10.0.0.5", url: "http://10.0.0.5:3500/v1.0/healthz"},
+		{host: "::1,127.0.0.1", addresses: []string{"::1", "127.0.0.1"}, localHost: "::1", url: "http://[::1]:3500/v1.0/healthz"},
+		{host: "0.0.0.0", addresses: []string{"0.0.0.0"}, localHost: "127.0.0.1", url: "http://127.0.0.1:3500/v1.0/healthz"},
+		{host: "::", addresses: []string{"::"}, localHost: "::1", url: "http://[::1]:3500/v1.0/healthz"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			pf := &PortForward{Host: tc.host, LocalPort: 3500}
+			assert.Equal(t, tc.addresses, pf.addresses())
+			assert.Equal(t, tc.localHost, pf.localHost())
+			assert.Equal(t, tc.url, makeRawEndpoint("http", pf, daprHealthzPath))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// NewRateLimiter returns a token bucket letting rps requests per second
+// through, whatever the number of workers sharing it. 0 means no limit.
+func NewRateLimiter(rps float64) (*rate.Limiter, error) {
+	if rps < 0 || math.IsNaN(rps) || math.IsInf(rps, 0) {
+		return nil, &UsageError{fmt.Errorf("invalid rate %v, expected requests per second or 0 for no limit", rps)}
+	}
+	if rps == 0 {
+		return rate.NewLimiter(rate.Inf, 1), nil
+	}
+	// a burst of one spaces the requests evenly.
+	return rate.NewLimiter(rate.Limit(rps), 1), nil
+}
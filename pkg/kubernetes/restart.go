@@ -0,0 +1,182 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/tkeel-io/cli/pkg/print"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// restartedAtAnnotation is the pod template annotation set by kubectl rollout
+// restart, changing it rolls out new pods.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// PluginRestart is a rollout restart of the deployments of a plugin.
+type PluginRestart struct {
+	PluginID string
+	// Deployments restarted, as namespace/name.
+	Deployments []string
+	// OldPods of the plugin when the restart started.
+	OldPods []string
+
+	deployments []types.NamespacedName
+}
+
+// RestartPlugin rolls out a restart of the deployments running the pods of
+// the plugin, like kubectl rollout restart, by patching the restartedAt
+// annotation of their pod template.
+func RestartPlugin(pluginID string) (*PluginRestart, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+	list, err := GetAppPods(client, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	r := &PluginRestart{PluginID: pluginID}
+	seen := map[types.NamespacedName]bool{}
+	for _, app := range list {
+		r.OldPods = append(r.OldPods, app.PodName)
+		name, err := podDeployment(ctx, client, app.pod)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			r.deployments = append(r.deployments, name)
+			r.Deployments = append(r.Deployments, name.String())
+		}
+	}
+	sort.Strings(r.OldPods)
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	for _, d := range r.deployments {
+		_, err = client.AppsV1().Deployments(d.Namespace).Patch(ctx, d.Name, types.StrategicMergePatchType, []byte(patch), v1.PatchOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error restart deployment %s: %w", d, err)
+		}
+	}
+	return r, nil
+}
+
+// podDeployment returns the deployment owning the replica set of the pod.
+func podDeployment(ctx context.Context, client k8s.Interface, p *DaprPod) (types.NamespacedName, error) {
+	pod := (*core_v1.Pod)(p)
+	notManaged := fmt.Errorf("pod %s/%s is not managed by a deployment, delete it to restart it", pod.Namespace, pod.Name)
+	owner := v1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "ReplicaSet" {
+		return types.NamespacedName{}, notManaged
+	}
+	rs, err := client.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, v1.GetOptions{})
+	if err != nil {
+		return types.NamespacedName{}, fmt.Errorf("error get replica set %s/%s: %w", pod.Namespace, owner.Name, err)
+	}
+	owner = v1.GetControllerOf(rs)
+	if owner == nil || owner.Kind != "Deployment" {
+		return types.NamespacedName{}, notManaged
+	}
+	return types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, nil
+}
+
+// Wait waits until the rollout of every deployment of the restart is
+// complete and returns the ready pods of the plugin that replaced the old
+// ones. Progress is reported on stderr while waiting.
+func (r *PluginRestart) Wait(ctx context.Context) ([]string, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+	ticker := time.NewTicker(waitReadyPollInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	var lastReport time.Time
+	for {
+		pods, state, err := r.restartedPods(ctx, client)
+		if err != nil || state == "" {
+			return pods, err
+		}
+		if lastReport.IsZero() || time.Since(lastReport) >= waitReadyReportInterval {
+			print.InfoStatusEvent(os.Stderr, "Waiting for the restart of %s after %s: %s", r.PluginID, time.Since(start).Round(time.Second), state)
+			lastReport = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w: restart of %s not complete after %s, %s", ErrPodNotRunning, r.PluginID, time.Since(start).Round(time.Second), state)
+			}
+			return nil, fmt.Errorf("wait for the restart of %s: %w", r.PluginID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// restartedPods returns the new ready pods of the plugin once the rollouts
+// are complete, or describes the state of the first one still in progress.
+func (r *PluginRestart) restartedPods(ctx context.Context, client k8s.Interface) ([]string, string, error) {
+	for _, name := range r.deployments {
+		d, err := client.AppsV1().Deployments(name.Namespace).Get(ctx, name.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("error get deployment %s: %w", name, err)
+		}
+		if state := rolloutState(d); state != "" {
+			return nil, fmt.Sprintf("deployment %s %s", name, state), nil
+		}
+	}
+
+	list, err := GetAppPods(client, r.PluginID)
+	if errors.Is(err, ErrAppNotFound) {
+		return nil, "no pod found", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	old := make(map[string]bool, len(r.OldPods))
+	for _, name := range r.OldPods {
+		old[name] = true
+	}
+	var pods []string
+	for _, app := range list {
+		if !old[app.PodName] && podReady(app.pod) {
+			pods = append(pods, app.PodName)
+		}
+	}
+	if len(pods) == 0 {
+		return nil, "no new pod ready", nil
+	}
+	sort.Strings(pods)
+	return pods, "", nil
+}
+
+// rolloutState describes a rollout of the deployment in progress, it is empty
+// once the rollout is complete, following kubectl rollout status.
+func rolloutState(d *apps_v1.Deployment) string {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	switch {
+	case d.Generation > d.Status.ObservedGeneration:
+		return "is waiting for the restart to be observed"
+	case d.Status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("has %d of %d new replicas updated", d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return fmt.Sprintf("has %d old replicas pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		return fmt.Sprintf("has %d of %d updated replicas available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	terrors "github.com/tkeel-io/kit/errors"
+	"github.com/tkeel-io/kit/result"
+	securityApi "github.com/tkeel-io/tkeel/api/security/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	_listTenantRolesMethodFormat = "apis/rudder/v1/tenants/%s/roles"
+)
+
+type RoleListOutPut struct {
+	ID   string `csv:"ID"   json:"id"   yaml:"id"`
+	Name string `csv:"NAME" json:"name" yaml:"name"`
+}
+
+// TenantRoles lists the roles available in the tenant.
+func TenantRoles(tenantID string) ([]RoleListOutPut, error) {
+	token, err := getAdminToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "error get token")
+	}
+	method := fmt.Sprintf(_listTenantRolesMethodFormat, tenantID)
+
+	resp, err := InvokeByPortForward(_pluginKeel, method, nil, http.MethodGet, setAuthenticate(token))
+	if err != nil {
+		return nil, errors.Wrap(err, "error invoke")
+	}
+
+	var r = &result.Http{}
+	if err = protojson.Unmarshal([]byte(resp), r); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal")
+	}
+
+	if r.Code != terrors.Success.Reason {
+		return nil, errors.Wrap(errors.New(r.Msg), "error response code")
+	}
+
+	response := securityApi.ListRolesResponse{}
+	err = r.Data.UnmarshalTo(&response)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshal response")
+	}
+
+	var list = make([]RoleListOutPut, 0, len(response.Roles))
+	for _, role := range response.Roles {
+		list = append(list, RoleListOutPut{role.Id, role.Name})
+	}
+	return list, nil
+}
+
+// resolveRoles maps role names or IDs onto the IDs of the available roles.
+func resolveRoles(available []RoleListOutPut, wanted []string) ([]string, error) {
+	ids := make([]string, 0, len(wanted))
+	for _, w := range wanted {
+		id := ""
+		for _, role := range available {
+			if role.ID == w || role.Name == w {
+				id = role.ID
+				break
+			}
+		}
+		if id == "" {
+			names := make([]string, 0, len(available))
+			for _, role := range available {
+				names = append(names, role.Name)
+			}
+			return nil, &UsageError{fmt.Errorf("unknown role %q, valid roles are: %s", w, strings.Join(names, ", "))}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
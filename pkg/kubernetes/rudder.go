@@ -22,10 +22,20 @@ const (
 )
 
 func AdminLogin(password string) (token string, err error) {
+	resp, err := adminLogin(password)
+	if err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
+
+// adminLogin logs the admin in and caches the issued token for the
+// commands calling the tKeel APIs.
+func adminLogin(password string) (*oauth2.IssueTokenResponse, error) {
 	password = base64.StdEncoding.EncodeToString([]byte(password))
 	u, err := url.Parse(_adminLoginMethod)
 	if err != nil {
-		return "", errors.Wrap(err, "parse admin login method error")
+		return nil, errors.Wrap(err, "parse admin login method error")
 	}
 	val := u.Query()
 	val.Set("password", password)
@@ -33,43 +43,44 @@ func AdminLogin(password string) (token string, err error) {
 
 	resp, err := InvokeByPortForward(_pluginRudder, u.String(), nil, http.MethodGet)
 	if err != nil {
-		return "", errors.Wrap(err, "invoking admin login err")
+		return nil, errors.Wrap(err, "invoking admin login err")
 	}
-	token, err = getToken(resp)
+	tokenResponse, err := getToken(resp)
 	if err != nil {
-		return "", errors.Wrap(err, "get token err")
+		return nil, errors.Wrap(err, "get token err")
 	}
 
 	f, err := fileutil.LocateAdminToken(fileutil.RewriteFlag())
 	if err != nil {
-		return "", errors.Wrap(err, "open rudder token failed")
+		return nil, errors.Wrap(err, "open rudder token failed")
 	}
-	if _, err = f.WriteString(token); err != nil {
-		return "", errors.Wrap(err, "write token to file failed")
+	defer f.Close()
+	if _, err = f.WriteString(tokenResponse.AccessToken); err != nil {
+		return nil, errors.Wrap(err, "write token to file failed")
 	}
 
-	return token, nil
+	return tokenResponse, nil
 }
 
-func getToken(body string) (string, error) {
-	tokenResponse := oauth2.IssueTokenResponse{}
+func getToken(body string) (*oauth2.IssueTokenResponse, error) {
+	tokenResponse := &oauth2.IssueTokenResponse{}
 
 	var r = &result.Http{}
 	if err := protojson.Unmarshal([]byte(body), r); err != nil {
-		return "", errors.Wrap(err, "unmarshal response context error")
+		return nil, errors.Wrap(err, "unmarshal response context error")
 	}
 
 	if r.Code == "io.tkeel.rudder.api.oauth2.v1.OAUTH2_ERR_PASSWORD_NOT_MATCH" {
-		return "", errors.New("invalid password")
+		return nil, errors.New("invalid password")
 	}
 
 	if r.Code != terrors.Success.Reason {
-		return "", fmt.Errorf("invalid response: %s", r.Msg)
+		return nil, fmt.Errorf("invalid response: %s", r.Msg)
 	}
 
-	if err := r.Data.UnmarshalTo(&tokenResponse); err != nil {
-		return "", errors.Wrap(err, "unmarshal response data to token error")
+	if err := r.Data.UnmarshalTo(tokenResponse); err != nil {
+		return nil, errors.Wrap(err, "unmarshal response data to token error")
 	}
 
-	return tokenResponse.AccessToken, nil
+	return tokenResponse, nil
 }
@@ -0,0 +1,144 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tkeel-io/cli/pkg/print"
+	core_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// ErrServiceNotReady is returned when a service has no ready pod behind the
+// targeted port.
+var ErrServiceNotReady = errors.New("no ready endpoint")
+
+// ServiceTarget is a port of a kubernetes service, reached directly rather
+// than through a dapr sidecar.
+type ServiceTarget struct {
+	Namespace string
+	Name      string
+	// Port is the number or the name of the port of the service.
+	Port string
+}
+
+func (t *ServiceTarget) String() string {
+	return fmt.Sprintf("%s/%s:%s", t.Namespace, t.Name, t.Port)
+}
+
+// ParseServiceTarget parses a [namespace/]service:port target, the
+// namespace defaults to the one of the current context.
+func ParseServiceTarget(target string) (*ServiceTarget, error) {
+	t := &ServiceTarget{}
+	name := target
+	if i := strings.Index(name, "/"); i >= 0 {
+		t.Namespace, name = name[:i], name[i+1:]
+	}
+	i := strings.LastIndex(name, ":")
+	if i < 0 {
+		return nil, &UsageError{fmt.Errorf("invalid service %q, expected namespace/service:port", target)}
+	}
+	t.Name, t.Port = name[:i], name[i+1:]
+	if t.Name == "" || t.Port == "" || strings.Contains(t.Namespace, ":") || strings.ContainsAny(t.Name, "/:") {
+		return nil, &UsageError{fmt.Errorf("invalid service %q, expected namespace/service:port", target)}
+	}
+	if t.Namespace == "" {
+		if t.Namespace, _ = lookupNamespace(); t.Namespace == v1.NamespaceAll {
+			t.Namespace = v1.NamespaceDefault
+		}
+	}
+	return t, nil
+}
+
+// GetPortforwardForService forwards a local port to the target port of a
+// ready pod behind the service port. Only the options not tied to a dapr
+// app, such as WithAddress, WithLocalPort and WithProgress, apply.
+func GetPortforwardForService(target *ServiceTarget, options ...PortForwardConfigureOption) (*PortForward, error) {
+	config, client, err := GetKubeConfigClient()
+	if err != nil {
+		return nil, fmt.Errorf("get kube config error: %w", err)
+	}
+
+	podName, port, err := resolveServicePod(client, target)
+	if err != nil {
+		return nil, err
+	}
+	print.Verbosef(print.VerbosityEndpoints, "Using pod %s/%s:%d of service %s", target.Namespace, podName, port, target)
+
+	portForward, err := NewPortForward(
+		config,
+		target.Namespace, podName,
+		DefaultAddress,
+		0,
+		port,
+		print.Verbose(print.VerbosityPortForward),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new portforward failed: %w", err)
+	}
+	// the service stands in for the app, it has no dapr ports.
+	app := &AppPod{AppInfo: AppInfo{AppID: target.Name, PodName: podName, Namespace: target.Namespace}}
+	portForward.App = app
+	for i := 0; i < len(options); i++ {
+		if err := options[i](portForward, app); err != nil {
+			return nil, fmt.Errorf("set portforward options failed: %w", err)
+		}
+	}
+	portForward.stopOnInterrupt()
+	return portForward, nil
+}
+
+// resolveServicePod returns a ready pod behind the port of the service and
+// the container port the service port targets on it.
+func resolveServicePod(client k8s.Interface, target *ServiceTarget) (string, int, error) {
+	ctx := context.Background()
+	svc, err := client.CoreV1().Services(target.Namespace).Get(ctx, target.Name, v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", 0, fmt.Errorf("service %s/%s %w", target.Namespace, target.Name, ErrAppNotFound)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error get service %s/%s: %w", target.Namespace, target.Name, err)
+	}
+	svcPort, err := findServicePort(svc, target.Port)
+	if err != nil {
+		return "", 0, err
+	}
+
+	endpoints, err := client.CoreV1().Endpoints(target.Namespace).Get(ctx, target.Name, v1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", 0, fmt.Errorf("error get endpoints of service %s/%s: %w", target.Namespace, target.Name, err)
+	}
+	if err == nil {
+		for _, subset := range endpoints.Subsets {
+			for _, p := range subset.Ports {
+				if p.Name != svcPort.Name {
+					continue
+				}
+				for _, addr := range subset.Addresses {
+					if addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+						return addr.TargetRef.Name, int(p.Port), nil
+					}
+				}
+			}
+		}
+	}
+	return "", 0, fmt.Errorf("%w: %s of service %s/%s has no ready pod", ErrServiceNotReady, target.Port, target.Namespace, target.Name)
+}
+
+// findServicePort returns the port of the service with the given number or name.
+func findServicePort(svc *core_v1.Service, port string) (*core_v1.ServicePort, error) {
+	number, _ := strconv.Atoi(port)
+	names := make([]string, 0, len(svc.Spec.Ports))
+	for i, p := range svc.Spec.Ports {
+		if p.Name == port || (number != 0 && int(p.Port) == number) {
+			return &svc.Spec.Ports[i], nil
+		}
+		names = append(names, strconv.Itoa(int(p.Port)))
+	}
+	return nil, &UsageError{fmt.Errorf("service %s/%s has no port %s, its ports are: %s", svc.Namespace, svc.Name, port, strings.Join(names, ", "))}
+}
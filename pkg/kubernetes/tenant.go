@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	terrors "github.com/tkeel-io/kit/errors"
@@ -22,14 +25,20 @@ const (
 )
 
 type TenantListOutPut struct {
-	ID     string `csv:"ID"`
-	Title  string `csv:"TITLE"`
-	Remark string `csv:"REMARK"`
+	ID      string `csv:"ID"      json:"id"      yaml:"id"`
+	Title   string `csv:"TITLE"   json:"title"   yaml:"title"`
+	Remark  string `csv:"REMARK"  json:"remark"  yaml:"remark"`
+	Created string `csv:"CREATED" json:"created" yaml:"created"`
 }
 
-func TenantCreate(title, remark, adminName, adminPW string) error {
+// ErrTenantExists is returned when creating a tenant whose title is taken.
+var ErrTenantExists = errors.New("tenant already exists")
+
+// TenantCreate creates a tenant, with an admin user when adminName and
+// adminPW are both set, and returns the ID of the new tenant.
+func TenantCreate(title, remark, adminName, adminPW string) (string, error) {
 	if len(title) == 0 {
-		return errors.New("title param nil")
+		return "", errors.New("title param nil")
 	}
 	tenant := &TenantCreateIn{Title: title, Remark: remark}
 	if len(adminName) != 0 && len(adminPW) != 0 {
@@ -40,41 +49,62 @@ func TenantCreate(title, remark, adminName, adminPW string) error {
 	return CreateTenant(tenant)
 }
 
-func CreateTenant(tenant *TenantCreateIn) error {
+// CreateTenant creates the tenant and returns its ID.
+func CreateTenant(tenant *TenantCreateIn) (string, error) {
 	token, err := getAdminToken()
 	if err != nil {
-		return err
+		return "", err
 	}
 	method := _createTenantMethodFormat
 
 	data, err := json.Marshal(tenant)
 	if err != nil {
-		return errors.Wrap(err, "marshal plugin request failed")
+		return "", errors.Wrap(err, "marshal plugin request failed")
 	}
 	resp, err := InvokeByPortForward(_pluginKeel, method, data, http.MethodPost, setAuthenticate(token))
 	if err != nil {
-		return errors.Wrap(err, "invoke "+method+" error")
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+			return "", fmt.Errorf("%w: %s", ErrTenantExists, tenant.Title)
+		}
+		return "", errors.Wrap(err, "invoke "+method+" error")
 	}
 
 	var r = &result.Http{}
 	if err = protojson.Unmarshal([]byte(resp), r); err != nil {
-		return errors.Wrap(err, "can't unmarshal'")
+		return "", errors.Wrap(err, "can't unmarshal'")
 	}
 
 	if r.Code != terrors.Success.Reason {
-		return errors.New("response error: " + r.Msg)
+		return "", errors.New("response error: " + r.Msg)
 	}
 
-	return nil
+	response := tenantApi.CreateTenantResponse{}
+	if err = r.Data.UnmarshalTo(&response); err != nil {
+		return "", errors.Wrap(err, "error unmarshal response")
+	}
+
+	return response.TenantId, nil
 }
 
 func TenantList() ([]TenantListOutPut, error) {
+	return ListTenants("")
+}
+
+// ListTenants lists the tenants whose title contains search, all of them if
+// search is empty. The search is sent to the server and applied again here in
+// case the server ignores it.
+func ListTenants(search string) ([]TenantListOutPut, error) {
 	token, err := getAdminToken()
 	if err != nil {
 		return nil, errors.Wrap(err, "error get token")
 	}
 
-	resp, err := InvokeByPortForward(_pluginKeel, _listTenantsMethodFormat, nil, http.MethodGet, setAuthenticate(token))
+	params := url.Values{}
+	if search != "" {
+		params.Set("key_words", search)
+	}
+	resp, err := InvokeByPortForward(_pluginKeel, _listTenantsMethodFormat, nil, http.MethodGet, setAuthenticate(token), InvokeAddHTTPParams(params))
 	if err != nil {
 		return nil, errors.Wrap(err, "error invoke")
 	}
@@ -96,7 +126,10 @@ func TenantList() ([]TenantListOutPut, error) {
 
 	var list = make([]TenantListOutPut, 0, len(listResponse.Tenants))
 	for _, tenant := range listResponse.Tenants {
-		list = append(list, TenantListOutPut{tenant.TenantId, tenant.Title, tenant.Remark})
+		if search != "" && !strings.Contains(strings.ToLower(tenant.Title), strings.ToLower(search)) {
+			continue
+		}
+		list = append(list, TenantListOutPut{tenant.TenantId, tenant.Title, tenant.Remark, formatTimestamp(tenant.CreatedAt)})
 	}
 	return list, nil
 }
@@ -129,7 +162,7 @@ func TenantInfo(tenantID string) ([]TenantListOutPut, error) {
 	}
 
 	var list = make([]TenantListOutPut, 0, 1)
-	list = append(list, TenantListOutPut{tenantResponse.TenantId, tenantResponse.Title, tenantResponse.Remark})
+	list = append(list, TenantListOutPut{tenantResponse.TenantId, tenantResponse.Title, tenantResponse.Remark, formatTimestamp(tenantResponse.CreatedAt)})
 	return list, nil
 }
 
@@ -156,6 +189,45 @@ func TenantDelete(tenantID string) error {
 	return nil
 }
 
+// ErrTenantHasUsers is returned when deleting a tenant that still has users without force.
+var ErrTenantHasUsers = errors.New("tenant still has users")
+
+// TenantDeleteSummary counts what was removed along with a tenant.
+type TenantDeleteSummary struct {
+	Users   int
+	Plugins int
+}
+
+// DeleteTenant deletes the tenant. A tenant that still has users is only
+// deleted with force, the returned summary counts what went with it.
+func DeleteTenant(tenantID string, force bool) (*TenantDeleteSummary, error) {
+	summary, err := checkDeleteTenant(tenantID, force)
+	if err != nil {
+		return nil, err
+	}
+	if err = TenantDelete(tenantID); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// checkDeleteTenant counts what deleting the tenant would remove, refusing
+// a tenant with users unless force is set.
+func checkDeleteTenant(tenantID string, force bool) (*TenantDeleteSummary, error) {
+	users, err := TenantUserList(tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error list tenant users")
+	}
+	if len(users) > 0 && !force {
+		return nil, fmt.Errorf("%w: %d users in %s", ErrTenantHasUsers, len(users), tenantID)
+	}
+	plugins, err := ListPluginsOfTenant(tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error list tenant plugins")
+	}
+	return &TenantDeleteSummary{Users: len(users), Plugins: len(plugins)}, nil
+}
+
 func TenantPluginList(pluginID string) ([]TenantListOutPut, error) {
 	token, err := getAdminToken()
 	if err != nil {
@@ -186,11 +258,19 @@ func TenantPluginList(pluginID string) ([]TenantListOutPut, error) {
 
 	var list = make([]TenantListOutPut, 0, len(listResponse.Tenants))
 	for _, t := range listResponse.Tenants {
-		list = append(list, TenantListOutPut{t.TenantId, t.Title, t.Remark})
+		list = append(list, TenantListOutPut{ID: t.TenantId, Title: t.Title, Remark: t.Remark})
 	}
 	return list, nil
 }
 
+// formatTimestamp formats a millisecond unix timestamp of the tKeel API.
+func formatTimestamp(ms int64) string {
+	if ms == 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).Format("2006-01-02 15:04:05")
+}
+
 type TenantCreateIn struct {
 	Title  string      `json:"title"`
 	Remark string      `json:"remark"`
@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// curlCommand renders the curl command sending req with the body data,
+// credentials redacted.
+func curlCommand(req *http.Request, data []byte) string {
+	args := []string{"curl", "-X", req.Method, shellQuote(req.URL.String())}
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			args = append(args, "-H", shellQuote(k+": "+redactHeader(k, v)))
+		}
+	}
+	switch {
+	case len(data) == 0:
+	case utf8.Valid(data):
+		args = append(args, "--data-binary", shellQuote(string(data)))
+	default:
+		args = append(args, "--data-binary", fmt.Sprintf("@body.bin # %d bytes of binary data", len(data)))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// requestTimer records the timing of a request through httptrace.
+type requestTimer struct {
+	start, connectStart, connectDone, tlsStart, tlsDone, firstByte time.Time
+	reused                                                         bool
+}
+
+// trace returns req reporting its progress to t, starting the clock.
+func (t *requestTimer) trace(req *http.Request) *http.Request {
+	t.start = time.Now()
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn:              func(info httptrace.GotConnInfo) { t.reused = info.Reused },
+		ConnectStart:         func(string, string) { t.connectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { t.connectDone = time.Now() },
+		TLSHandshakeStart:    func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.tlsDone = time.Now() },
+		GotFirstResponseByte: func() { t.firstByte = time.Now() },
+	}))
+}
+
+// report writes the timing breakdown of the request, done when the response
+// was read. The port-forward listens on a local address, so there is no DNS
+// lookup to time.
+func (t *requestTimer) report(w io.Writer) {
+	since := func(from, to time.Time) string {
+		if from.IsZero() || to.IsZero() {
+			return "n/a"
+		}
+		return to.Sub(from).Round(time.Microsecond).String()
+	}
+	conn := "new connection"
+	if t.reused {
+		conn = "reused connection"
+	}
+	fmt.Fprintf(w, "* dns: n/a, connect: %s, tls: %s, ttfb: %s, total: %s (%s)\n",
+		since(t.connectStart, t.connectDone), since(t.tlsStart, t.tlsDone),
+		since(t.start, t.firstByte), since(t.start, time.Now()), conn)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 	terrors "github.com/tkeel-io/kit/errors"
@@ -17,32 +19,88 @@ const (
 	_createTenantUserMethodFormat = "apis/rudder/v1/tenants/%s/users"
 	_deleteTenantUserMethodFormat = "apis/rudder/v1/tenants/%s/users/%s"
 	_infoTenantUserMethodFormat   = "apis/rudder/v1/tenants/%s/users/%s"
+	_updateTenantUserMethodFormat = "apis/rudder/v1/tenants/%s/users/%s"
+	_resetPasswordKeyMethodFormat = "apis/rudder/v1/tenants/%s/users/%s/rpk"
+	_resetPasswordMethodFormat    = "apis/rudder/v1/tenants/users/pwd/reset"
 )
 
 type UserListOutPut struct {
-	ID       string `csv:"ID"`
-	Username string `csv:"USERNAME"`
-	TenantID string `csv:"TENANT ID"`
+	ID       string `csv:"ID"        json:"id"        yaml:"id"`
+	Username string `csv:"USERNAME"  json:"username"  yaml:"username"`
+	TenantID string `csv:"TENANT ID" json:"tenant_id" yaml:"tenant_id"`
 }
 
 type UserInfo struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Roles    []string `json:"roles,omitempty"`
 }
 
-func TenantUserCreate(tenantID, username, password string) error {
+// ErrUserExists is returned when creating a user whose username is taken.
+var ErrUserExists = errors.New("user already exists")
+
+// CreateTenantUser creates a user in the tenant and returns the new user's ID.
+func CreateTenantUser(tenantID, username, password string, roles []string) (string, error) {
 	token, err := getAdminToken()
 	if err != nil {
-		return err
+		return "", err
 	}
 	method := fmt.Sprintf(_createTenantUserMethodFormat, tenantID)
-	userinfo := UserInfo{Username: username, Password: password}
+	userinfo := UserInfo{Username: username, Password: password, Roles: roles}
 	data, err := json.Marshal(userinfo)
 	if err != nil {
-		return errors.Wrap(err, "error marshal")
+		return "", errors.Wrap(err, "error marshal")
 	}
 	resp, err := InvokeByPortForward(_pluginKeel, method, data, http.MethodPost, setAuthenticate(token))
 	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+			return "", fmt.Errorf("%w: %s", ErrUserExists, username)
+		}
+		return "", errors.Wrap(err, "invoke "+method+" error")
+	}
+
+	var r = &result.Http{}
+	if err = protojson.Unmarshal([]byte(resp), r); err != nil {
+		return "", errors.Wrap(err, "can't unmarshal'")
+	}
+
+	if r.Code != terrors.Success.Reason {
+		return "", errors.Wrap(errors.New(r.Msg), "error response code")
+	}
+
+	response := tenantApi.CreateUserResponse{}
+	if err = r.Data.UnmarshalTo(&response); err != nil {
+		return "", errors.Wrap(err, "error unmarshal response")
+	}
+
+	return response.UserId, nil
+}
+
+// ErrUserNotFound is returned when the user does not exist in the tenant.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrPermissionDenied is returned when the admin token may not manage the user.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// DeleteTenantUser removes the user from the tenant.
+func DeleteTenantUser(tenantID, userID string) error {
+	token, err := getAdminToken()
+	if err != nil {
+		return err
+	}
+	method := fmt.Sprintf(_deleteTenantUserMethodFormat, tenantID, userID)
+	resp, err := InvokeByPortForward(_pluginKeel, method, nil, http.MethodDelete, setAuthenticate(token))
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			switch statusErr.StatusCode {
+			case http.StatusNotFound:
+				return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+			case http.StatusUnauthorized, http.StatusForbidden:
+				return fmt.Errorf("%w: %s", ErrPermissionDenied, statusErr.Body)
+			}
+		}
 		return errors.Wrap(err, "invoke "+method+" error")
 	}
 
@@ -58,15 +116,62 @@ func TenantUserCreate(tenantID, username, password string) error {
 	return nil
 }
 
-// tenant user manage.
-func TenantUserDelete(tenantID, userID string) error {
+// SetUserRole replaces the roles of the user with the given ones. Roles may
+// be given by name or ID and are checked against the tenant's roles first.
+func SetUserRole(tenantID, userID string, roles []string) error {
+	available, err := TenantRoles(tenantID)
+	if err != nil {
+		return errors.Wrap(err, "error list roles")
+	}
+	ids, err := resolveRoles(available, roles)
+	if err != nil {
+		return err
+	}
+
 	token, err := getAdminToken()
 	if err != nil {
 		return err
 	}
-	method := fmt.Sprintf(_deleteTenantUserMethodFormat, tenantID, userID)
-	resp, err := InvokeByPortForward(_pluginKeel, method, nil, http.MethodDelete, setAuthenticate(token))
+	method := fmt.Sprintf(_updateTenantUserMethodFormat, tenantID, userID)
+	data, err := json.Marshal(map[string][]string{"roles": ids})
+	if err != nil {
+		return errors.Wrap(err, "error marshal")
+	}
+	resp, err := InvokeByPortForward(_pluginKeel, method, data, http.MethodPut, setAuthenticate(token))
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+		}
+		return errors.Wrap(err, "invoke "+method+" error")
+	}
+
+	var r = &result.Http{}
+	if err = protojson.Unmarshal([]byte(resp), r); err != nil {
+		return errors.Wrap(err, "can't unmarshal'")
+	}
+
+	if r.Code != terrors.Success.Reason {
+		return errors.Wrap(errors.New(r.Msg), "error response code")
+	}
+
+	return nil
+}
+
+// ResetUserPassword sets a new password for the user. It fetches a reset
+// key for the user first and then resets the password with it.
+func ResetUserPassword(tenantID, userID, password string) error {
+	token, err := getAdminToken()
+	if err != nil {
+		return err
+	}
+	method := fmt.Sprintf(_resetPasswordKeyMethodFormat, tenantID, userID)
+	resp, err := InvokeByPortForward(_pluginKeel, method, nil, http.MethodGet, setAuthenticate(token))
 	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+		}
 		return errors.Wrap(err, "invoke "+method+" error")
 	}
 
@@ -74,7 +179,28 @@ func TenantUserDelete(tenantID, userID string) error {
 	if err = protojson.Unmarshal([]byte(resp), r); err != nil {
 		return errors.Wrap(err, "can't unmarshal'")
 	}
+	if r.Code != terrors.Success.Reason {
+		return errors.Wrap(errors.New(r.Msg), "error response code")
+	}
+	keyResponse := tenantApi.GetResetPasswordKeyResponse{}
+	if err = r.Data.UnmarshalTo(&keyResponse); err != nil {
+		return errors.Wrap(err, "error unmarshal response")
+	}
 
+	method = _resetPasswordMethodFormat
+	data, err := json.Marshal(map[string]string{"reset_key": keyResponse.ResetKey, "new_password": password})
+	if err != nil {
+		return errors.Wrap(err, "error marshal")
+	}
+	resp, err = InvokeByPortForward(_pluginKeel, method, data, http.MethodPost, setAuthenticate(token))
+	if err != nil {
+		return errors.Wrap(err, "invoke "+method+" error")
+	}
+
+	r = &result.Http{}
+	if err = protojson.Unmarshal([]byte(resp), r); err != nil {
+		return errors.Wrap(err, "can't unmarshal'")
+	}
 	if r.Code != terrors.Success.Reason {
 		return errors.Wrap(errors.New(r.Msg), "error response code")
 	}
@@ -114,14 +240,83 @@ func TenantUserInfo(tenantID, userID string) ([]UserListOutPut, error) {
 	return list, nil
 }
 
+// defaultUserPageSize is the page size TenantAllUsers lists users with.
+const defaultUserPageSize = 100
+
+// invokeFunc sends a request to the keel plugin, over a new port-forward or
+// the one of an InvokeSession.
+type invokeFunc func(method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error)
+
 func TenantUserList(tenantID string) ([]UserListOutPut, error) {
+	list, _, err := TenantAllUsers(tenantID, 0)
+	return list, err
+}
+
+// TenantUsers lists the users of the tenant. When pageNum and pageSize are
+// set only that page is returned, otherwise the server default applies.
+func TenantUsers(tenantID string, pageNum, pageSize int) ([]UserListOutPut, error) {
 	token, err := getAdminToken()
 	if err != nil {
 		return nil, errors.Wrap(err, "error get token")
 	}
+	invoke := func(method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+		return InvokeByPortForward(_pluginKeel, method, data, verb, reqOpts...)
+	}
+	return tenantUsersPage(invoke, token, tenantID, pageNum, pageSize)
+}
+
+// TenantAllUsers lists the users of the tenant page by page until all are
+// collected. A positive limit caps the number of users returned, capped then
+// reports whether the tenant has more.
+func TenantAllUsers(tenantID string, limit int) (list []UserListOutPut, capped bool, err error) {
+	token, err := getAdminToken()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error get token")
+	}
+	// all the pages go over a single port-forward.
+	session, err := NewInvokeSession(_pluginKeel)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error invoke")
+	}
+	defer session.Close()
+	return collectTenantUsers(session.Invoke, token, tenantID, limit, defaultUserPageSize)
+}
+
+// collectTenantUsers is TenantAllUsers over invoke, pageSize users a page.
+func collectTenantUsers(invoke invokeFunc, token, tenantID string, limit, pageSize int) (list []UserListOutPut, capped bool, err error) {
+	for pageNum := 1; ; pageNum++ {
+		var users []UserListOutPut
+		users, err = tenantUsersPage(invoke, token, tenantID, pageNum, pageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		// a server ignoring page_num returns the first page again.
+		if len(users) > 0 && len(list) > 0 && users[0].ID == list[0].ID {
+			break
+		}
+		list = append(list, users...)
+		if limit > 0 && len(list) > limit {
+			return list[:limit], true, nil
+		}
+		if len(users) < pageSize {
+			break
+		}
+	}
+	return list, false, nil
+}
+
+func tenantUsersPage(invoke invokeFunc, token, tenantID string, pageNum, pageSize int) ([]UserListOutPut, error) {
 	method := fmt.Sprintf(_listTenantUserMethodFormat, tenantID)
 
-	resp, err := InvokeByPortForward(_pluginKeel, method, nil, http.MethodGet, setAuthenticate(token))
+	params := url.Values{}
+	if pageNum > 0 {
+		params.Set("page_num", strconv.Itoa(pageNum))
+	}
+	if pageSize > 0 {
+		params.Set("page_size", strconv.Itoa(pageSize))
+	}
+
+	resp, err := invoke(method, nil, http.MethodGet, setAuthenticate(token), InvokeAddHTTPParams(params))
 	if err != nil {
 		return nil, errors.Wrap(err, "error invoke")
 	}
@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/pkg/errors"
+)
+
+// UserImportRow is a user to create read from a CSV file, in the columns of
+// the user list output plus PASSWORD and ROLES. The ID column of an exported
+// list is ignored.
+type UserImportRow struct {
+	// Line of the row in the file, the header being line 1.
+	Line     int    `csv:"-"`
+	Username string `csv:"USERNAME"`
+	Password string `csv:"PASSWORD"`
+	// Roles are separated by semicolons.
+	Roles    string `csv:"ROLES"`
+	TenantID string `csv:"TENANT ID"`
+}
+
+// RoleList returns the roles of the row.
+func (r *UserImportRow) RoleList() []string {
+	var roles []string
+	for _, role := range strings.Split(r.Roles, ";") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// ReadUserImport parses the users of a CSV file with a header line.
+func ReadUserImport(r io.Reader) ([]*UserImportRow, error) {
+	var rows []*UserImportRow
+	if err := gocsv.Unmarshal(r, &rows); err != nil {
+		return nil, &UsageError{errors.Wrap(err, "error parse csv")}
+	}
+	for i, row := range rows {
+		row.Line = i + 2
+	}
+	return rows, nil
+}
+
+// CheckUserImport validates every row for an import into the tenant,
+// returning the error of each invalid row by line.
+func CheckUserImport(tenantID string, rows []*UserImportRow) map[int]error {
+	errs := map[int]error{}
+	seen := map[string]int{}
+	for _, row := range rows {
+		switch {
+		case row.Username == "":
+			errs[row.Line] = &UsageError{errors.New("missing USERNAME")}
+		case row.Password == "":
+			errs[row.Line] = &UsageError{fmt.Errorf("missing PASSWORD of user %s", row.Username)}
+		case row.TenantID != "" && row.TenantID != tenantID:
+			errs[row.Line] = &UsageError{fmt.Errorf("user %s belongs to tenant %s, not %s", row.Username, row.TenantID, tenantID)}
+		case seen[row.Username] != 0:
+			errs[row.Line] = &UsageError{fmt.Errorf("user %s already on line %d", row.Username, seen[row.Username])}
+		default:
+			seen[row.Username] = row.Line
+		}
+	}
+	return errs
+}
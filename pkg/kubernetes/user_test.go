@@ -0,0 +1,93 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	terrors "github.com/tkeel-io/kit/errors"
+	"github.com/tkeel-io/kit/result"
+	tenantApi "github.com/tkeel-io/tkeel/api/tenant/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// fakeUserServer serves the users u1..uN of a tenant page by page, the way
+// the keel plugin does, and records the pages asked for.
+type fakeUserServer struct {
+	total         int
+	ignorePageNum bool
+	pages         []int
+}
+
+func (s *fakeUserServer) invoke(method string, data []byte, verb string, reqOpts ...HTTPRequestOption) (string, error) {
+	req, err := http.NewRequest(verb, "http://127.0.0.1/"+method, nil)
+	if err != nil {
+		return "", err
+	}
+	for _, opt := range reqOpts {
+		if err = opt(req); err != nil {
+			return "", err
+		}
+	}
+	pageNum, _ := strconv.Atoi(req.URL.Query().Get("page_num"))
+	pageSize, _ := strconv.Atoi(req.URL.Query().Get("page_size"))
+	s.pages = append(s.pages, pageNum)
+	if s.ignorePageNum {
+		pageNum = 1
+	}
+
+	var users []string
+	for i := (pageNum-1)*pageSize + 1; i <= pageNum*pageSize && i <= s.total; i++ {
+		users = append(users, fmt.Sprintf(`{"user_id": "u%d", "username": "user%d", "tenant_id": "t1"}`, i, i))
+	}
+	resp := &tenantApi.ListUserResponse{}
+	if err = protojson.Unmarshal([]byte(`{"users": [`+strings.Join(users, ",")+`]}`), resp); err != nil {
+		return "", err
+	}
+	payload, err := anypb.New(resp)
+	if err != nil {
+		return "", err
+	}
+	b, err := protojson.Marshal(&result.Http{Code: terrors.Success.Reason, Data: payload})
+	return string(b), err
+}
+
+func Test_collectTenantUsers(t *testing.T) {
+	const pageSize = 3
+	testCases := []struct {
+		name          string
+		total         int
+		limit         int
+		ignorePageNum bool
+		wantUsers     int
+		wantCapped    bool
+		wantPages     []int
+	}{
+		{name: "empty tenant", total: 0, wantUsers: 0, wantPages: []int{1}},
+		{name: "partial page", total: 2, wantUsers: 2, wantPages: []int{1}},
+		{name: "exactly one page", total: 3, wantUsers: 3, wantPages: []int{1, 2}},
+		{name: "several pages", total: 7, wantUsers: 7, wantPages: []int{1, 2, 3}},
+		{name: "limit caps", total: 7, limit: 4, wantUsers: 4, wantCapped: true, wantPages: []int{1, 2}},
+		{name: "limit on page boundary", total: 7, limit: 3, wantUsers: 3, wantCapped: true, wantPages: []int{1, 2}},
+		{name: "limit equals total", total: 3, limit: 3, wantUsers: 3, wantPages: []int{1, 2}},
+		{name: "limit above total", total: 7, limit: 10, wantUsers: 7, wantPages: []int{1, 2, 3}},
+		{name: "server ignoring page_num", total: 7, ignorePageNum: true, wantUsers: 3, wantPages: []int{1, 2}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &fakeUserServer{total: tc.total, ignorePageNum: tc.ignorePageNum}
+			list, capped, err := collectTenantUsers(server.invoke, "token", "t1", tc.limit, pageSize)
+			assert.NoError(t, err)
+			assert.Len(t, list, tc.wantUsers)
+			assert.Equal(t, tc.wantCapped, capped)
+			assert.Equal(t, tc.wantPages, server.pages)
+			for i, user := range list {
+				assert.Equal(t, fmt.Sprintf("u%d", i+1), user.ID)
+			}
+		})
+	}
+}
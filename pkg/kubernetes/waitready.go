@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tkeel-io/cli/pkg/print"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	waitReadyPollInterval   = 2 * time.Second
+	waitReadyReportInterval = 10 * time.Second
+)
+
+// WaitAppPodReady polls the pods of the app until the pod named podName, or
+// any of them if empty, is running with all its containers ready, and returns
+// it. Progress is reported on stderr while waiting, and an ErrPodNotRunning
+// error is returned once timeout has passed without a ready pod.
+func WaitAppPodReady(ctx context.Context, client k8s.Interface, appID, podName string, timeout time.Duration) (*AppPod, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitReadyPollInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	var lastReport time.Time
+	for {
+		app, state, err := readyAppPod(client, appID, podName)
+		if err != nil || app != nil {
+			return app, err
+		}
+		if lastReport.IsZero() {
+			print.InfoStatusEvent(os.Stderr, "Waiting up to %s for %s to be ready: %s", timeout, appID, state)
+			lastReport = time.Now()
+		} else if time.Since(lastReport) >= waitReadyReportInterval {
+			print.InfoStatusEvent(os.Stderr, "Still waiting for %s after %s: %s", appID, time.Since(start).Round(time.Second), state)
+			lastReport = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w: %s not ready after %s, %s", ErrPodNotRunning, appID, timeout, state)
+			}
+			return nil, fmt.Errorf("wait for %s to be ready: %w", appID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// readyAppPod returns the ready pod of the app, or describes the state of the
+// pod waited for when none is ready yet.
+func readyAppPod(client k8s.Interface, appID, podName string) (*AppPod, string, error) {
+	lookup := GetAppPods
+	if podName != "" {
+		// a pinned pod settles which namespace the app is picked in.
+		lookup = appPods
+	}
+	list, err := lookup(client, appID)
+	if errors.Is(err, ErrAppNotFound) {
+		// right after a deploy the pods may not be created yet.
+		return nil, "no pod found", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	state := ""
+	for _, app := range list {
+		if podName != "" && app.PodName != podName {
+			continue
+		}
+		if podReady(app.pod) {
+			return app, "", nil
+		}
+		if state == "" {
+			s := podStatusOutput(app)
+			state = fmt.Sprintf("%s/%s is %s with %s containers ready", s.Namespace, s.PodName, s.Phase, s.Ready)
+		}
+	}
+	if state == "" {
+		state = fmt.Sprintf("pod %s not found", podName)
+	}
+	return nil, state, nil
+}
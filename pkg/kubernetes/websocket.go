@@ -0,0 +1,316 @@
+package kubernetes
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/cli/pkg/print"
+)
+
+const (
+	// websocketCloseTimeout bounds how long to wait for the server to answer a close.
+	websocketCloseTimeout = 5 * time.Second
+	// websocketWriteWait bounds how long writing a control frame may take.
+	websocketWriteWait = 10 * time.Second
+	// DefaultHandshakeTimeout bounds the websocket upgrade unless told otherwise.
+	DefaultHandshakeTimeout = 45 * time.Second
+	// handshakeBodyLimit caps how much of a failed upgrade response is reported.
+	handshakeBodyLimit = 4 << 10
+)
+
+// DefaultWebsocketClient is the WebsocketClient used by WebsocketByPortForward.
+var DefaultWebsocketClient = &WebsocketClient{HandshakeTimeout: DefaultHandshakeTimeout}
+
+// WebsocketClient holds the settings of a websocket connection to a plugin.
+type WebsocketClient struct {
+	// Input, when set, is read line by line and every line is sent as a
+	// message. The connection is closed once Input is exhausted.
+	Input io.Reader
+	// PingInterval, when set, sends a ping at this interval to keep the
+	// connection alive. By default no read deadline is set and the client
+	// waits for server messages indefinitely; with PingInterval set the
+	// connection is dropped when no pong arrives within two intervals.
+	PingInterval time.Duration
+	// Pod pins the connection to the named pod of the plugin, the first
+	// running pod is used if empty.
+	Pod string
+	// HandshakeTimeout bounds how long the websocket upgrade may take, zero
+	// means no limit.
+	HandshakeTimeout time.Duration
+	// Header is sent with the websocket upgrade request, e.g. for auth.
+	Header http.Header
+	// Subprotocols are offered to the server in Sec-WebSocket-Protocol, in
+	// order of preference.
+	Subprotocols []string
+	// Binary sends the data and the lines of Input as binary messages
+	// instead of text ones.
+	Binary bool
+	// BinaryOutput, when set, receives the payload of the binary messages of
+	// the server as is. Otherwise they are printed hex encoded, one per line.
+	BinaryOutput io.Writer
+	// Record, when set, receives every text and binary frame sent and
+	// received, with its time and type, as NDJSON.
+	Record io.Writer
+	// Replay, when set, sends the sent frames of a recorded session in place
+	// of Input, keeping the delays between them, then closes the connection.
+	Replay []WebsocketFrame
+}
+
+// WebsocketByPortForward websocket request to the k8s pod.
+func WebsocketByPortForward(pluginID, method string, data []byte) (string, error) {
+	return DefaultWebsocketClient.ByPortForward(pluginID, method, data)
+}
+
+// ByPortForward connects to the websocket method of the plugin through a
+// port-forward and prints the messages of the server until it is closed.
+func (c *WebsocketClient) ByPortForward(pluginID, method string, data []byte) (string, error) {
+	portForward, err := GetPortforwardForPod(pluginID, c.Pod, WithAppPort, WithProgress)
+	if err != nil {
+		return "", err
+	}
+	defer portForward.Stop()
+
+	// initialize port forwarding
+	if err = portForward.Init(); err != nil {
+		return "", err
+	}
+
+	endpoint := makeWsEndpoint(portForward, method)
+	print.Verbosef(print.VerbosityEndpoints, "Connecting to %s", endpoint)
+
+	dialer := websocket.Dialer{HandshakeTimeout: c.HandshakeTimeout, Subprotocols: c.Subprotocols}
+	logHeaders(">", c.Header)
+	connect, resp, err := dialer.Dial(endpoint, c.Header)
+	if nil != err {
+		return "", handshakeError(err, resp)
+	}
+	if p := connect.Subprotocol(); p != "" {
+		print.Verbosef(print.VerbosityEndpoints, "Using subprotocol %s", p)
+	}
+	defer resp.Body.Close()
+	defer connect.Close()
+
+	var rec *frameRecorder
+	if c.Record != nil {
+		rec = &frameRecorder{w: c.Record}
+	}
+	if len(data) > 0 || (c.Input == nil && c.Replay == nil) {
+		if err = send(connect, rec, c.messageType(), data); err != nil {
+			return "", err
+		}
+	}
+
+	// answer pings ourselves, the read loop below is the only reader.
+	connect.SetPingHandler(func(appData string) error {
+		err := connect.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(websocketWriteWait))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+
+	closing := make(chan struct{})
+	if c.PingInterval > 0 {
+		// the pong handler runs in the read loop, install it before anything
+		// else uses the connection.
+		c.watchPongs(connect, closing)
+	}
+	switch {
+	case c.Input != nil:
+		go c.sendInput(connect, rec, closing)
+	case c.Replay != nil:
+		go c.sendReplay(connect, rec, closing)
+	}
+	if c.PingInterval > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go c.keepAlive(connect, done)
+	}
+
+	for {
+		messageType, messageData, err := connect.ReadMessage()
+		if nil != err {
+			select {
+			case <-closing:
+				// we started the close handshake, so the connection ending is expected.
+				return "", nil
+			default:
+			}
+			return "", closeError(err)
+		}
+		// close frames never show up here, ReadMessage returns them as a *websocket.CloseError.
+		if messageType == websocket.TextMessage || messageType == websocket.BinaryMessage {
+			if err = rec.record(FrameReceived, messageType, messageData); err != nil {
+				return "", err
+			}
+		}
+		switch messageType {
+		case websocket.TextMessage:
+			fmt.Println(string(messageData))
+		case websocket.BinaryMessage:
+			if err = c.writeBinary(messageData); err != nil {
+				return "", err
+			}
+		case websocket.PingMessage:
+		case websocket.PongMessage:
+		default:
+		}
+	}
+}
+
+func (c *WebsocketClient) messageType() int {
+	if c.Binary {
+		return websocket.BinaryMessage
+	}
+	return websocket.TextMessage
+}
+
+// writeBinary writes a binary message to BinaryOutput, or prints it hex
+// encoded when BinaryOutput is not set.
+func (c *WebsocketClient) writeBinary(data []byte) error {
+	if c.BinaryOutput == nil {
+		fmt.Println(hex.EncodeToString(data))
+		return nil
+	}
+	if _, err := c.BinaryOutput.Write(data); err != nil {
+		return errors.Wrap(err, "error write binary message")
+	}
+	return nil
+}
+
+// handshakeError adds the status and body of the response to a rejected
+// upgrade, so a 401 tells apart from a 404 or a 500.
+func handshakeError(err error, resp *http.Response) error {
+	if resp == nil {
+		return errors.Wrap(err, "connect error")
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, handshakeBodyLimit))
+	return errors.Wrap(&StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}, "websocket handshake failed")
+}
+
+// closeError reports a normal close by the server and returns nil for it.
+// Abnormal closures and other read errors are returned as errors.
+func closeError(err error) error {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return errors.Wrap(err, "websocket read error")
+	}
+
+	msg := fmt.Sprintf("connection closed with code %d", closeErr.Code)
+	if closeErr.Text != "" {
+		msg = fmt.Sprintf("%s: %s", msg, closeErr.Text)
+	}
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+		print.InfoStatusEvent(os.Stderr, "Server %s", msg)
+		return nil
+	}
+	return errors.New(msg)
+}
+
+// send writes a message and records it as sent.
+func send(connect *websocket.Conn, rec *frameRecorder, messageType int, data []byte) error {
+	if err := connect.WriteMessage(messageType, data); err != nil {
+		return errors.Wrap(err, "websocket write error")
+	}
+	return rec.record(FrameSent, messageType, data)
+}
+
+// sendInput writes every line of Input as a message. Once Input is
+// exhausted it starts the close handshake.
+func (c *WebsocketClient) sendInput(connect *websocket.Conn, rec *frameRecorder, closing chan<- struct{}) {
+	scanner := bufio.NewScanner(c.Input)
+	for scanner.Scan() {
+		if err := send(connect, rec, c.messageType(), scanner.Bytes()); err != nil {
+			print.WarningStatusEvent(os.Stderr, "%s", err)
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		print.WarningStatusEvent(os.Stderr, "error reading input: %s", err)
+	}
+	closeSession(connect, closing)
+}
+
+// sendReplay writes the sent frames of Replay, waiting between them as long
+// as when they were recorded. Once done it starts the close handshake.
+func (c *WebsocketClient) sendReplay(connect *websocket.Conn, rec *frameRecorder, closing chan<- struct{}) {
+	var last time.Time
+	for i := range c.Replay {
+		f := &c.Replay[i]
+		if f.Direction != FrameSent {
+			continue
+		}
+		if !last.IsZero() && f.Time.After(last) {
+			time.Sleep(f.Time.Sub(last))
+		}
+		last = f.Time
+		messageType, data, err := f.payload()
+		if err == nil {
+			err = send(connect, rec, messageType, data)
+		}
+		if err != nil {
+			print.WarningStatusEvent(os.Stderr, "%s", err)
+			return
+		}
+	}
+	closeSession(connect, closing)
+}
+
+// closeSession closes closing and starts the close handshake.
+func closeSession(connect *websocket.Conn, closing chan<- struct{}) {
+	close(closing)
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := connect.WriteMessage(websocket.CloseMessage, msg); err != nil {
+		connect.Close()
+		return
+	}
+	// don't wait forever for the server to answer the close.
+	connect.SetReadDeadline(time.Now().Add(websocketCloseTimeout))
+}
+
+// watchPongs sets a read deadline of two PingIntervals, extended whenever a
+// pong arrives, so that a server that stopped answering ends the read loop.
+func (c *WebsocketClient) watchPongs(connect *websocket.Conn, closing <-chan struct{}) {
+	deadline := 2 * c.PingInterval
+	connect.SetReadDeadline(time.Now().Add(deadline))
+	connect.SetPongHandler(func(string) error {
+		select {
+		case <-closing:
+			// keep the close timeout set by closeSession.
+			return nil
+		default:
+		}
+		return connect.SetReadDeadline(time.Now().Add(deadline))
+	})
+}
+
+// keepAlive pings the server every PingInterval until done is closed.
+func (c *WebsocketClient) keepAlive(connect *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(c.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := connect.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// not use dapr api.
+func makeWsEndpoint(pf *PortForward, method string) string {
+	return localURL("ws", pf.localHost(), pf.LocalPort, method)
+}
@@ -0,0 +1,114 @@
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// Directions of a recorded websocket frame.
+const (
+	FrameSent     = "sent"
+	FrameReceived = "received"
+)
+
+// Types of a recorded websocket frame.
+const (
+	FrameText   = "text"
+	FrameBinary = "binary"
+)
+
+// maxFrameLineSize bounds the size of one recorded frame.
+const maxFrameLineSize = 16 * 1024 * 1024
+
+// WebsocketFrame is a message of a websocket session, recorded as one line
+// of NDJSON. The data of binary frames is base64 encoded.
+type WebsocketFrame struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Type      string    `json:"type"`
+	Data      string    `json:"data"`
+}
+
+// payload returns the websocket message type and the decoded data of the frame.
+func (f *WebsocketFrame) payload() (int, []byte, error) {
+	switch f.Type {
+	case FrameText:
+		return websocket.TextMessage, []byte(f.Data), nil
+	case FrameBinary:
+		data, err := base64.StdEncoding.DecodeString(f.Data)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid base64 data: %w", err)
+		}
+		return websocket.BinaryMessage, data, nil
+	default:
+		return 0, nil, fmt.Errorf("invalid frame type %q, allowed values are: %s, %s", f.Type, FrameText, FrameBinary)
+	}
+}
+
+// ReadWebsocketFrames reads a session recorded with WebsocketClient.Record,
+// blank lines are skipped.
+func ReadWebsocketFrames(r io.Reader) ([]WebsocketFrame, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxFrameLineSize)
+	frames := []WebsocketFrame{}
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var f WebsocketFrame
+		if err := json.Unmarshal(line, &f); err != nil {
+			return nil, &UsageError{fmt.Errorf("line %d is not a recorded frame: %w", n, err)}
+		}
+		if f.Direction != FrameSent && f.Direction != FrameReceived {
+			return nil, &UsageError{fmt.Errorf("line %d: invalid direction %q, allowed values are: %s, %s", n, f.Direction, FrameSent, FrameReceived)}
+		}
+		if _, _, err := f.payload(); err != nil {
+			return nil, &UsageError{fmt.Errorf("line %d: %w", n, err)}
+		}
+		frames = append(frames, f)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading recorded frames after line %d: %w", n, err)
+	}
+	return frames, nil
+}
+
+// frameRecorder appends the frames of a session to w, one JSON object per
+// line. It is safe for concurrent use and a nil recorder records nothing.
+type frameRecorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (r *frameRecorder) record(direction string, messageType int, data []byte) error {
+	if r == nil {
+		return nil
+	}
+	f := WebsocketFrame{Time: time.Now(), Direction: direction, Type: FrameText, Data: string(data)}
+	if messageType == websocket.BinaryMessage {
+		f.Type, f.Data = FrameBinary, base64.StdEncoding.EncodeToString(data)
+	}
+	line, err := json.Marshal(&f)
+	if err != nil {
+		return errors.Wrap(err, "error encode frame")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err = r.w.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "error record frame")
+	}
+	return nil
+}
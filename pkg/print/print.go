@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"runtime"
 	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 )
 
 const (
@@ -39,11 +41,47 @@ func EnableJSONFormat() {
 	logAsJSON = true
 }
 
+var quiet bool
+
+// EnableQuiet suppresses all status events but failures, which are moved to
+// stderr, so that stdout only carries the result of a command.
+func EnableQuiet() {
+	quiet = true
+}
+
+// noColor is set by DisableColor or the NO_COLOR environment variable.
+var noColor = noColorEnv()
+
+func noColorEnv() bool {
+	_, ok := os.LookupEnv("NO_COLOR")
+	return ok
+}
+
+// DisableColor turns off colors and animations in all the output.
+func DisableColor() {
+	noColor = true
+	color.NoColor = true
+}
+
+// ColorEnabled reports whether output is colored. Color is off when NO_COLOR
+// is set, when stdout is not a terminal or after DisableColor.
+func ColorEnabled() bool {
+	return !noColor && !color.NoColor
+}
+
+// plain reports whether status events are printed without their symbols.
+func plain() bool {
+	return runtime.GOOS == windowsOS || !ColorEnabled()
+}
+
 // SuccessStatusEvent reports on a success event.
 func SuccessStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	if quiet {
+		return
+	}
 	if logAsJSON {
 		logJSON(w, "success", fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
+	} else if plain() {
 		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
 	} else {
 		fmt.Fprintf(w, "✅  %s\n", fmt.Sprintf(fmtstr, a...))
@@ -52,9 +90,12 @@ func SuccessStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
 
 // FailureStatusEvent reports on a failure event.
 func FailureStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	if quiet {
+		w = os.Stderr
+	}
 	if logAsJSON {
 		logJSON(w, "failure", fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
+	} else if plain() {
 		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
 	} else {
 		fmt.Fprintf(w, "❌  %s\n", fmt.Sprintf(fmtstr, a...))
@@ -63,9 +104,12 @@ func FailureStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
 
 // WarningStatusEvent reports on a failure event.
 func WarningStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	if quiet {
+		return
+	}
 	if logAsJSON {
 		logJSON(w, "warning", fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
+	} else if plain() {
 		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
 	} else {
 		fmt.Fprintf(w, "⚠  %s\n", fmt.Sprintf(fmtstr, a...))
@@ -74,9 +118,12 @@ func WarningStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
 
 // PendingStatusEvent reports on a pending event.
 func PendingStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	if quiet {
+		return
+	}
 	if logAsJSON {
 		logJSON(w, "pending", fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
+	} else if plain() {
 		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
 	} else {
 		fmt.Fprintf(w, "⌛  %s\n", fmt.Sprintf(fmtstr, a...))
@@ -85,9 +132,12 @@ func PendingStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
 
 // InfoStatusEvent reports status information on an event.
 func InfoStatusEvent(w io.Writer, fmtstr string, a ...interface{}) {
+	if quiet {
+		return
+	}
 	if logAsJSON {
 		logJSON(w, "info", fmt.Sprintf(fmtstr, a...))
-	} else if runtime.GOOS == windowsOS {
+	} else if plain() {
 		fmt.Fprintf(w, "%s\n", fmt.Sprintf(fmtstr, a...))
 	} else {
 		fmt.Fprintf(w, "ℹ️  %s\n", fmt.Sprintf(fmtstr, a...))
@@ -99,12 +149,15 @@ func Spinner(w io.Writer, fmtstr string, a ...interface{}) (func(msg string), fu
 	var once sync.Once
 	var s *spinner.Spinner
 
-	if logAsJSON {
+	switch {
+	case quiet:
+		// nothing is shown until the result, which only reports a failure.
+	case logAsJSON:
 		logJSON(w, "pending", msg)
-	} else if runtime.GOOS == windowsOS {
+	case plain():
 		fmt.Fprintf(w, "%s\n", msg)
 		return func(string) {}, func(Result) {} // Return a dummy func
-	} else {
+	default:
 		s = spinner.New(spinner.CharSets[0], 100*time.Millisecond)
 		s.Writer = w
 		_ = s.Color("cyan")
@@ -113,7 +166,9 @@ func Spinner(w io.Writer, fmtstr string, a ...interface{}) (func(msg string), fu
 	}
 
 	return func(msg string) {
-			s.Suffix = msg
+			if s != nil {
+				s.Suffix = msg
+			}
 		}, func(result Result) {
 			once.Do(func() {
 				if s != nil {
@@ -128,6 +183,37 @@ func Spinner(w io.Writer, fmtstr string, a ...interface{}) (func(msg string), fu
 		}
 }
 
+// Interactive reports whether w is a terminal that status animations can be
+// drawn on, rather than a pipe, a file or a JSON log, and color is not
+// disabled.
+func Interactive(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || logAsJSON || noColor {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Progress draws a spinner with the message on w until the returned func is
+// called, which clears it. Nothing is drawn when w is not Interactive, so
+// piped output and logs never get control characters.
+func Progress(w io.Writer, fmtstr string, a ...interface{}) func() {
+	if quiet || plain() || !Interactive(w) {
+		return func() {}
+	}
+
+	s := spinner.New(spinner.CharSets[0], 100*time.Millisecond)
+	s.Writer = w
+	_ = s.Color("cyan")
+	s.Suffix = fmt.Sprintf("  %s", fmt.Sprintf(fmtstr, a...))
+	s.Start()
+
+	var once sync.Once
+	return func() {
+		once.Do(s.Stop)
+	}
+}
+
 func logJSON(w io.Writer, status, message string) {
 	type jsonLog struct {
 		Time    time.Time `json:"time"`
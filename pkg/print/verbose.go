@@ -0,0 +1,33 @@
+package print
+
+import "os"
+
+// Verbosity levels raised by repeating the --verbose flag.
+const (
+	// VerbosityEndpoints reports the endpoints called and the pods chosen.
+	VerbosityEndpoints = 1
+	// VerbosityHeaders also reports request and response headers.
+	VerbosityHeaders = 2
+	// VerbosityPortForward also emits the logs of the port-forwards.
+	VerbosityPortForward = 3
+)
+
+var verbosity int
+
+// SetVerbosity sets the level Verbose and Verbosef compare against.
+func SetVerbosity(level int) {
+	verbosity = level
+}
+
+// Verbose reports whether messages of the given level are printed.
+func Verbose(level int) bool {
+	return verbosity >= level
+}
+
+// Verbosef reports status information on stderr when the verbosity is at
+// least level, keeping it apart from the command output on stdout.
+func Verbosef(level int, fmtstr string, a ...interface{}) {
+	if Verbose(level) {
+		InfoStatusEvent(os.Stderr, fmtstr, a...)
+	}
+}
@@ -1,8 +1,18 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
 )
 
 // ParseInstallArg parse the first arg, get repo, plugin and version information.
@@ -42,3 +52,199 @@ func GetRealPath(path string) (string, error) {
 	}
 	return path, nil
 }
+
+// ParseHeaders parses "Key: Value" formatted strings into a http.Header.
+// Repeated keys keep all their values.
+func ParseHeaders(headers []string) (http.Header, error) {
+	h := make(http.Header, len(headers))
+	for _, header := range headers {
+		i := strings.Index(header, ":")
+		if i == -1 || strings.TrimSpace(header[:i]) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected format is 'Key: Value'", header)
+		}
+		h.Add(strings.TrimSpace(header[:i]), strings.TrimSpace(header[i+1:]))
+	}
+	return h, nil
+}
+
+// ParseParams parses "key=value" formatted strings into url.Values.
+// Repeated keys keep all their values.
+func ParseParams(params []string) (url.Values, error) {
+	values := make(url.Values, len(params))
+	for _, param := range params {
+		i := strings.Index(param, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid param %q, expected format is 'key=value'", param)
+		}
+		values.Add(param[:i], param[i+1:])
+	}
+	return values, nil
+}
+
+// QuoteETag returns etag as sent in If-Match and If-None-Match, quoting it
+// unless it is already quoted, weak (W/"...") or the * wildcard.
+func QuoteETag(etag string) string {
+	etag = strings.TrimSpace(etag)
+	if etag == "*" || strings.HasPrefix(etag, `W/"`) || (len(etag) >= 2 && strings.HasPrefix(etag, `"`) && strings.HasSuffix(etag, `"`)) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// ParseJSONPath parses a JSONPath template in the syntax of kubectl -o
+// jsonpath, e.g. {.items[*].id}. The braces may be left out of a single
+// expression, e.g. .items[0].id.
+func ParseJSONPath(expr string) (*jsonpath.JSONPath, error) {
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New("jsonpath")
+	if err := jp.Parse(expr); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	return jp, nil
+}
+
+// ExecuteJSONPath returns the values of the JSON document data matched by jp.
+// Integers are kept as written in data, however large.
+func ExecuteJSONPath(jp *jsonpath.JSONPath, data []byte) (string, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("the body is not JSON: %w", err)
+	}
+	var b strings.Builder
+	if err := jp.Execute(&b, jsonNumbers(v)); err != nil {
+		return "", fmt.Errorf("error apply JSONPath: %w", err)
+	}
+	return b.String(), nil
+}
+
+// jsonNumbers replaces the json.Number values of v by an int64, or by a
+// float64 when they are not integers, so that filters can compare them.
+// Integers beyond int64 are kept as json.Number rather than rounded.
+func jsonNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if !strings.ContainsAny(string(t), ".eE") {
+			return t
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = jsonNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = jsonNumbers(e)
+		}
+	}
+	return v
+}
+
+var pathPlaceholder = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// ExpandPath substitutes the {name} placeholders of path with the path
+// escaped values of args, e.g. devices/{id}/status with id=abc. It fails
+// when a placeholder is left without a value.
+func ExpandPath(path string, args url.Values) (string, error) {
+	var missing []string
+	expanded := pathPlaceholder.ReplaceAllStringFunc(path, func(m string) string {
+		name := m[1 : len(m)-1]
+		if _, ok := args[name]; !ok {
+			missing = append(missing, m)
+			return m
+		}
+		return url.PathEscape(args.Get(name))
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for %s in %q", strings.Join(missing, ", "), path)
+	}
+	return expanded, nil
+}
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv substitutes the ${VAR} references of data with the values lookup
+// returns for them, e.g. os.LookupEnv. It fails when a variable is unset,
+// unless allowEmpty is set and it becomes empty. Other uses of $ are kept.
+func ExpandEnv(data []byte, lookup func(string) (string, bool), allowEmpty bool) ([]byte, error) {
+	var missing []string
+	expanded := envReference.ReplaceAllFunc(data, func(m []byte) []byte {
+		name := string(m[2 : len(m)-1])
+		value, ok := lookup(name)
+		if !ok && !allowEmpty {
+			if !containsString(missing, name) {
+				missing = append(missing, name)
+			}
+			return m
+		}
+		return []byte(value)
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment variable %s not set", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	passwordLower   = "abcdefghijkmnopqrstuvwxyz"
+	passwordUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordDigits  = "23456789"
+	passwordSymbols = "!@#$%^&*-_=+"
+)
+
+// GeneratePassword returns a random password of the given length that
+// contains at least one lower case letter, upper case letter, digit and symbol.
+func GeneratePassword(length int) (string, error) {
+	classes := []string{passwordLower, passwordUpper, passwordDigits, passwordSymbols}
+	if length < len(classes) {
+		return "", fmt.Errorf("password length must be at least %d", len(classes))
+	}
+	all := strings.Join(classes, "")
+
+	pw := make([]byte, length)
+	for i := range pw {
+		charset := all
+		if i < len(classes) {
+			charset = classes[i]
+		}
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		pw[i] = c
+	}
+	// Shuffle so the guaranteed characters are not always in front.
+	for i := len(pw) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		pw[i], pw[j] = pw[j], pw[i]
+	}
+	return string(pw), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,3 +33,159 @@ func TestParseInstallArg(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    http.Header
+		wantErr bool
+	}{
+		{"single header", []string{"Authorization: Bearer x"}, http.Header{"Authorization": {"Bearer x"}}, false},
+		{"repeated header", []string{"x-trace: a", "X-Trace:b"}, http.Header{"X-Trace": {"a", "b"}}, false},
+		{"value with colon", []string{"Referer: http://localhost:8080"}, http.Header{"Referer": {"http://localhost:8080"}}, false},
+		{"missing colon", []string{"Authorization Bearer x"}, nil, true},
+		{"missing key", []string{": value"}, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header, err := ParseHeaders(test.input)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, header)
+		})
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		args    url.Values
+		want    string
+		wantErr bool
+	}{
+		{"no placeholder", "v1/devices", nil, "v1/devices", false},
+		{"single placeholder", "devices/{id}/status", url.Values{"id": {"abc123"}}, "devices/abc123/status", false},
+		{"repeated placeholder", "{id}/{id}", url.Values{"id": {"a"}}, "a/a", false},
+		{"escaped value", "devices/{id}", url.Values{"id": {"a b/c"}}, "devices/a%20b%2Fc", false},
+		{"unused arg", "devices", url.Values{"id": {"a"}}, "devices", false},
+		{"missing arg", "devices/{id}/{field}", url.Values{"id": {"a"}}, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := ExpandPath(test.path, test.args)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, path)
+		})
+	}
+}
+
+func TestQuoteETag(t *testing.T) {
+	tests := []struct {
+		name string
+		etag string
+		want string
+	}{
+		{"bare", "abc", `"abc"`},
+		{"quoted", `"abc"`, `"abc"`},
+		{"weak", `W/"abc"`, `W/"abc"`},
+		{"wildcard", "*", "*"},
+		{"spaces", ` "abc" `, `"abc"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, QuoteETag(test.etag))
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	body := []byte(`{"items":[{"id":"a","size":12345678901},{"id":"b","size":2}],"total":2,"serial":123456789012345678901234,"ratio":0.5}`)
+	tests := []struct {
+		name    string
+		expr    string
+		body    []byte
+		want    string
+		wantErr bool
+	}{
+		{"single value", "{.total}", body, "2", false},
+		{"without braces", ".items[0].id", body, "a", false},
+		{"all matches", "{.items[*].id}", body, "a b", false},
+		{"large number", "{.items[0].size}", body, "12345678901", false},
+		{"beyond int64", "{.serial}", body, "123456789012345678901234", false},
+		{"float", "{.ratio}", body, "0.5", false},
+		{"range", `{range .items[*]}{.id}={.size}{"\n"}{end}`, body, "a=12345678901\nb=2\n", false},
+		{"filter", "{.items[?(@.size>2)].id}", body, "a", false},
+		{"missing key", "{.missing}", body, "", true},
+		{"not json", "{.total}", []byte("<html>"), "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(test.expr)
+			assert.NoError(t, err)
+			got, err := ExecuteJSONPath(jp, test.body)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	_, err := ParseJSONPath("{.items[}")
+	assert.Error(t, err)
+}
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{"ID": "abc", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	tests := []struct {
+		name       string
+		data       string
+		allowEmpty bool
+		want       string
+		wantErr    bool
+	}{
+		{"no reference", `{"price":"$5"}`, false, `{"price":"$5"}`, false},
+		{"reference", `{"id":"${ID}","again":"${ID}"}`, false, `{"id":"abc","again":"abc"}`, false},
+		{"set but empty", `{"v":"${EMPTY}"}`, false, `{"v":""}`, false},
+		{"bare dollar kept", `{"id":"$ID"}`, false, `{"id":"$ID"}`, false},
+		{"unset", `{"id":"${ID}","token":"${TOKEN}"}`, false, "", true},
+		{"unset allowed", `{"token":"${TOKEN}"}`, true, `{"token":""}`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ExpandEnv([]byte(test.data), lookup, test.allowEmpty)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, string(got))
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	pw, err := GeneratePassword(16)
+	assert.NoError(t, err)
+	assert.Len(t, pw, 16)
+	for _, charset := range []string{passwordLower, passwordUpper, passwordDigits, passwordSymbols} {
+		assert.True(t, strings.ContainsAny(pw, charset), "missing one of %q in %q", charset, pw)
+	}
+
+	_, err = GeneratePassword(3)
+	assert.Error(t, err)
+}